@@ -1,34 +1,69 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/rifkiadrn/cassandra-explore/config"
+	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/telemetry"
+	"gorm.io/gorm"
 )
 
 func main() {
 	viperConfig := config.NewViper()
 	log := config.NewLogger(viperConfig)
-	db := config.NewDatabase(viperConfig, log)
-	noSQLDB := config.NewNoSQLDatabase(viperConfig, log)
+	recorder := telemetry.NewRecorder()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// storage.mode memory serves everything from process memory, no database is connected
+	var (
+		db      *gorm.DB
+		noSQLDB repository.NoSQLSession
+	)
+	if !config.IsMemoryStorage(viperConfig) {
+		db = config.NewDatabase(viperConfig, log, recorder)
+		if viperConfig.GetBool("database.cassandra_migrate_on_start") {
+			if _, err := config.NewCQLMigrator(viperConfig, log).Up(ctx); err != nil {
+				log.Fatalf("Failed to apply CQL migrations: %v", err)
+			}
+		}
+		noSQLDB = config.NewNoSQLDatabase(viperConfig, log, recorder)
+	}
 	validate := config.NewValidator(viperConfig)
 	app := config.NewFiber(viperConfig)
 
-	fmt.Printf("noSQLDB: %d \n", noSQLDB)
-
-	config.Bootstrap(&config.BootstrapConfig{
+	background := config.Bootstrap(&config.BootstrapConfig{
+		Context:  ctx,
 		DB:       db,
 		NoSQLDB:  noSQLDB,
 		App:      app,
 		Log:      log,
 		Validate: validate,
 		Config:   viperConfig,
+		Recorder: recorder,
 	})
 
+	// stop taking requests on a signal, then let the background workers drain
+	go func() {
+		<-ctx.Done()
+		if err := app.Shutdown(); err != nil {
+			log.Warnf("Failed to shut down server: %v", err)
+		}
+	}()
+
 	webPort := viperConfig.GetInt("app.port")
 	fmt.Println(webPort)
 	err := app.Listen(fmt.Sprintf(":%d", webPort))
 	if err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
+
+	stop()
+	background.Wait()
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/rifkiadrn/cassandra-explore/config"
+	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+)
+
+// backfill copies Postgres users and blogs into the Cassandra keyspace
+func main() {
+	checkpoint := flag.String("checkpoint", "backfill.checkpoint.json", "checkpoint file, delete it to start over")
+	batchSize := flag.Int("batch-size", 500, "rows read from Postgres per batch")
+	concurrency := flag.Int("concurrency", 16, "concurrent Cassandra writes per batch")
+	dryRun := flag.Bool("dry-run", false, "read and count rows without writing to Cassandra or the checkpoint")
+	only := flag.String("only", "", "backfill only users or blogs")
+	flag.Parse()
+
+	viperConfig := config.NewViper()
+	log := config.NewLogger(viperConfig)
+	db := config.NewDatabase(viperConfig, log, nil)
+	noSQLDB := config.NewNoSQLDatabase(viperConfig, log, nil)
+	defer noSQLDB.Close()
+
+	backfillUseCase := usecase.NewBackfillUseCase(log,
+		repository.NewUserRepository(db, log),
+		repository.NewBlogRepository(db, log),
+		repository.NewUserRepositoryNoSQL(noSQLDB, log),
+		config.NewBlogRepositoryNoSQL(viperConfig, noSQLDB, log),
+		repository.NewFileCheckpointStore(*checkpoint),
+		usecase.BackfillConfig{
+			BatchSize:   *batchSize,
+			Concurrency: *concurrency,
+			DryRun:      *dryRun,
+			Users:       *only == "" || *only == "users",
+			Blogs:       *only == "" || *only == "blogs",
+		})
+
+	report, err := backfillUseCase.Run(context.Background())
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(report)
+
+	if err != nil {
+		log.Fatalf("Backfill stopped, rerun to resume from the checkpoint: %v", err)
+	}
+}
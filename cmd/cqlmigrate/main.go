@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/rifkiadrn/cassandra-explore/config"
+)
+
+// cqlmigrate applies or lists the CQL migrations under db/cql/migrations: cqlmigrate up|status
+func main() {
+	flag.Parse()
+
+	viperConfig := config.NewViper()
+	log := config.NewLogger(viperConfig)
+	migrator := config.NewCQLMigrator(viperConfig, log)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	switch flag.Arg(0) {
+	case "up":
+		applied, err := migrator.Up(context.Background())
+		_ = encoder.Encode(applied)
+		if err != nil {
+			log.Fatalf("CQL migration failed: %v", err)
+		}
+	case "status":
+		migrations, err := migrator.Status(context.Background())
+		if err != nil {
+			log.Fatalf("CQL migration status failed: %v", err)
+		}
+		_ = encoder.Encode(migrations)
+	default:
+		log.Fatalf("Usage: cqlmigrate up|status")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"time"
+
+	"github.com/rifkiadrn/cassandra-explore/config"
+	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+)
+
+// export dumps a Cassandra table as NDJSON or CSV, reading its token ranges in parallel
+func main() {
+	table := flag.String("table", "", "table to export, e.g. users or blogs.blogs_by_author")
+	format := flag.String("format", usecase.ExportNDJSON, "ndjson or csv")
+	out := flag.String("out", "", "output file, stdout when empty. Only a file can be resumed")
+	checkpoint := flag.String("checkpoint", "", "checkpoint file, <out>.checkpoint.json by default, delete it to start over")
+	splits := flag.Int("splits", 256, "token ranges the ring is cut into")
+	workers := flag.Int("workers", 8, "token ranges read at once")
+	retries := flag.Int("retries", 3, "further attempts of a failed token range")
+	retryBackoff := flag.Duration("retry-backoff", 500*time.Millisecond, "wait before the first retry of a range, doubling after")
+	pageSize := flag.Int("page-size", 1000, "rows per page inside a range")
+	progress := flag.Duration("progress", 10*time.Second, "progress log interval, 0 disables it")
+	flag.Parse()
+
+	viperConfig := config.NewViper()
+	log := config.NewLogger(viperConfig)
+	if *table == "" {
+		log.Fatalf("-table is required")
+	}
+	noSQLDB := config.NewNoSQLDatabase(viperConfig, log, nil)
+	defer noSQLDB.Close()
+
+	output := os.Stdout
+	var checkpointStore usecase.ICheckpointStore
+	if *out != "" {
+		file, err := os.OpenFile(*out, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			log.Fatalf("Failed open %s: %v", *out, err)
+		}
+		defer file.Close()
+		output = file
+
+		if *checkpoint == "" {
+			*checkpoint = *out + ".checkpoint.json"
+		}
+		checkpointStore = repository.NewFileCheckpointStore(*checkpoint)
+	}
+
+	exportUseCase := usecase.NewExportUseCase(log,
+		repository.NewTableScannerNoSQL(noSQLDB, config.CassandraKeyspace(viperConfig), *pageSize),
+		output,
+		checkpointStore,
+		usecase.ExportConfig{
+			Table:            *table,
+			Format:           *format,
+			Splits:           *splits,
+			Workers:          *workers,
+			MaxRetries:       *retries,
+			RetryBackoff:     *retryBackoff,
+			ProgressInterval: *progress,
+		})
+
+	report, err := exportUseCase.Run(context.Background())
+
+	// rows may be going to stdout
+	encoder := json.NewEncoder(os.Stderr)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(report)
+
+	if err != nil {
+		log.Fatalf("Export stopped, rerun with the same flags to resume from the checkpoint: %v", err)
+	}
+}
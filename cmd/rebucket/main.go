@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/rifkiadrn/cassandra-explore/config"
+	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+)
+
+// rebucket rewrites blogs_by_author into the time bucketed blogs_by_author_bucket table
+func main() {
+	checkpoint := flag.String("checkpoint", "rebucket.checkpoint.json", "checkpoint file, delete it to start over")
+	batchSize := flag.Int("batch-size", 500, "rows read from blogs_by_author per page")
+	concurrency := flag.Int("concurrency", 16, "concurrent Cassandra writes per page")
+	dryRun := flag.Bool("dry-run", false, "read and count rows without writing or moving the checkpoint")
+	flag.Parse()
+
+	viperConfig := config.NewViper()
+	log := config.NewLogger(viperConfig)
+	noSQLDB := config.NewNoSQLDatabase(viperConfig, log, nil)
+	defer noSQLDB.Close()
+
+	rebucketUseCase := usecase.NewRebucketUseCase(log,
+		repository.NewBlogLegacyRepositoryNoSQL(noSQLDB),
+		config.NewBlogRepositoryNoSQL(viperConfig, noSQLDB, log),
+		repository.NewFileCheckpointStore(*checkpoint),
+		usecase.RebucketConfig{
+			BatchSize:   *batchSize,
+			Concurrency: *concurrency,
+			DryRun:      *dryRun,
+		})
+
+	report, err := rebucketUseCase.Run(context.Background())
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(report)
+
+	if err != nil {
+		log.Fatalf("Rebucket stopped, rerun to resume from the checkpoint: %v", err)
+	}
+}
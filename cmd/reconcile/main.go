@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/rifkiadrn/cassandra-explore/config"
+)
+
+// reconcile prints the drift between Postgres and Cassandra as JSON and exits 2 when they are out of sync
+func main() {
+	repair := flag.Bool("repair", false, "repair drift in the direction set by reconcile.repair")
+	flag.Parse()
+
+	viperConfig := config.NewViper()
+	log := config.NewLogger(viperConfig)
+	db := config.NewDatabase(viperConfig, log, nil)
+	noSQLDB := config.NewNoSQLDatabase(viperConfig, log, nil)
+	defer noSQLDB.Close()
+
+	reconcileUseCase := config.NewReconcileUseCase(viperConfig, db, noSQLDB, log)
+
+	report, err := reconcileUseCase.Run(context.Background(), *repair)
+	if err != nil {
+		log.Fatalf("Reconcile failed: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(report)
+
+	if !report.InSync {
+		os.Exit(2)
+	}
+}
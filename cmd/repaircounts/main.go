@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/rifkiadrn/cassandra-explore/config"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+)
+
+// repaircounts recomputes the per author blog counts from the blog rows of every store keeping blogs
+func main() {
+	batchSize := flag.Int("batch-size", 500, "users read per page")
+	concurrency := flag.Int("concurrency", 16, "authors counted concurrently")
+	dryRun := flag.Bool("dry-run", false, "report the drift without correcting it")
+	flag.Parse()
+
+	viperConfig := config.NewViper()
+	log := config.NewLogger(viperConfig)
+	db := config.NewDatabase(viperConfig, log, nil)
+	noSQLDB := config.NewNoSQLDatabase(viperConfig, log, nil)
+	defer noSQLDB.Close()
+
+	repairs := config.NewBlogCountRepairs(viperConfig, db, noSQLDB, log, usecase.BlogCountRepairConfig{
+		BatchSize:   *batchSize,
+		Concurrency: *concurrency,
+		DryRun:      *dryRun,
+	})
+
+	var reports []entity.BlogCountRepairReport
+	var repairErr error
+	for _, repair := range repairs {
+		report, err := repair.Repair(context.Background())
+		reports = append(reports, report)
+		if err != nil {
+			repairErr = err
+			break
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(reports)
+
+	if repairErr != nil {
+		log.Fatalf("Repair stopped, rerun to continue, counts already corrected stay correct: %v", repairErr)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"time"
+
+	"github.com/rifkiadrn/cassandra-explore/config"
+	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+)
+
+// retag files the blogs written before tags existed under their #tags
+func main() {
+	checkpoint := flag.String("checkpoint", "retag.checkpoint.json", "checkpoint file, deleting it files every blog again and recounts the Cassandra tag counters")
+	batchSize := flag.Int("batch-size", 500, "blogs read per page")
+	concurrency := flag.Int("concurrency", 16, "blogs filed concurrently per page")
+	dryRun := flag.Bool("dry-run", false, "read and count blogs without filing them or moving the checkpoint")
+	before := flag.String("before", "", "only file blogs written before this RFC 3339 time, e.g. when tags were deployed")
+	flag.Parse()
+
+	viperConfig := config.NewViper()
+	log := config.NewLogger(viperConfig)
+
+	var beforeTime time.Time
+	if *before != "" {
+		var err error
+		if beforeTime, err = time.Parse(time.RFC3339, *before); err != nil {
+			log.Fatalf("Invalid -before: %v", err)
+		}
+	}
+
+	db := config.NewDatabase(viperConfig, log, nil)
+	noSQLDB := config.NewNoSQLDatabase(viperConfig, log, nil)
+	defer noSQLDB.Close()
+
+	retagUseCase := config.NewRetagUseCase(viperConfig, db, noSQLDB, log,
+		repository.NewFileCheckpointStore(*checkpoint),
+		usecase.RetagConfig{
+			BatchSize:   *batchSize,
+			Concurrency: *concurrency,
+			DryRun:      *dryRun,
+			Before:      beforeTime,
+		})
+
+	report, err := retagUseCase.Run(context.Background())
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(report)
+
+	if err != nil {
+		log.Fatalf("Retag stopped, rerun to resume from the checkpoint: %v", err)
+	}
+}
@@ -1,15 +1,18 @@
 package config
 
 import (
-	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"context"
+	"sync"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
-	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
 	"github.com/rifkiadrn/cassandra-explore/internal/handler/rest"
 	"github.com/rifkiadrn/cassandra-explore/internal/handler/rest/middleware"
 	"github.com/rifkiadrn/cassandra-explore/internal/handler/rest/router"
 	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/telemetry"
 	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
 	"github.com/rifkiadrn/cassandra-explore/internal/utils"
 	"github.com/sirupsen/logrus"
@@ -18,49 +21,138 @@ import (
 )
 
 type BootstrapConfig struct {
+	Context  context.Context // Done on shutdown, stops the background workers
 	DB       *gorm.DB
-	NoSQLDB  *gocql.Session
+	NoSQLDB  repository.NoSQLSession
 	App      *fiber.App
 	Log      *logrus.Logger
 	Validate *validator.Validate
 	Config   *viper.Viper
+	Recorder *telemetry.Recorder
 }
 
-func Bootstrap(config *BootstrapConfig) {
+// Bootstrap wires the app and returns the background work to wait for on shutdown
+func Bootstrap(config *BootstrapConfig) *sync.WaitGroup {
+	var background sync.WaitGroup
 
-	// setup repositories
-	userRepository := repository.NewUserRepository(config.DB, config.Log)
-	userRepositoryNoSQL := repository.NewUserRepositoryNoSQL(config.NoSQLDB)
-	blogRepository := repository.NewBlogRepository(config.DB, config.Log)
+	// setup repositories, in process when storage.mode is memory
+	memory := IsMemoryStorage(config.Config)
+	var repositories Repositories
+	if memory {
+		config.Log.Warn("storage.mode is memory, nothing is persisted")
+		repositories = NewMemoryRepositories(config.Config, config.Log)
+	} else {
+		repositories = NewRepositories(config.Config, config.DB, config.NoSQLDB, config.Log)
+	}
+	userRepository, userRepositoryNoSQL := repositories.User, repositories.UserNoSQL
+	blogRepository := repositories.Blog
 
 	// setup JWT manager
 	jwtManager := utils.NewJWTManager(config.Config.GetString("SECRET_KEY")) // TODO: move to config
 
 	// setup use cases
-	// dbTrx/unitOfWork
-	unitOfWork := context_db.NewGormUnitOfWork(config.DB)
+	// replicate users to cassandra through the outbox when a copy is configured
+	var outboxRepository usecase.IOutboxRepo
+	var outboxHandler *rest.OutboxHandler
+	if userRepositoryNoSQL != nil {
+		outboxRepo := repository.NewOutboxRepository(config.DB, config.Log)
+		outboxRepository = outboxRepo
+
+		outboxRelay := NewOutboxRelay(config.Config, repositories.UnitOfWork, config.Log, outboxRepo)
+		outboxRelay.Handle(entity.OutboxEventUserCreated, usecase.NewUserCreatedHandler(userRepositoryNoSQL))
+		outboxRelay.Handle(entity.OutboxEventUserUpdated, usecase.NewUserUpdatedHandler(userRepositoryNoSQL))
+		go outboxRelay.Run(config.Context)
+
+		outboxHandler = rest.NewOutboxHandler(outboxRelay, config.Log)
+	}
+
+	userUseCase := usecase.NewUserUseCase(repositories.UserUnitOfWork, config.Log, config.Validate, userRepository, outboxRepository,
+		repositories.BlogCount, jwtManager)
 
-	userUseCase := usecase.NewUserUseCase(unitOfWork, config.Log, config.Validate, userRepository, userRepositoryNoSQL, jwtManager)
+	// repeat a sample of reads on the other store of dual resources
+	var shadowHandler *rest.ShadowHandler
+	var shadowReader *usecase.ShadowReader
+	if !memory {
+		shadowReader = NewShadowReader(config.Config, config.Log)
+	}
+	if shadowReader != nil {
+		shadowHandler = rest.NewShadowHandler(shadowReader, config.Log)
+
+		if primary, secondary := NewShadowUserRepositories(config.Config, config.DB, config.NoSQLDB, config.Log); secondary != nil {
+			userUseCase = userUseCase.WithShadowReads(primary, secondary, shadowReader)
+		}
+	}
 
 	userHandler := rest.NewUserHandler(userUseCase, config.Log)
 
-	blogUsecase := usecase.NewBlogUseCase(unitOfWork, config.Log, config.Validate, blogRepository)
+	timelineUseCase := NewTimelineUseCase(config.Config, config.Log, userRepository, blogRepository, repositories.Follow, repositories.Timeline)
+
+	timelineHandler := rest.NewTimelineHandler(timelineUseCase, config.Log)
+
+	// queued fan-outs are finished on shutdown
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		timelineUseCase.Run(config.Context)
+	}()
+
+	blogUsecase := usecase.NewBlogUseCase(repositories.BlogUnitOfWork, config.Log, config.Validate, blogRepository, repositories.BlogCount,
+		repositories.BlogRevision, timelineUseCase)
+	if shadowReader != nil {
+		if primary, secondary := NewShadowBlogRepositories(config.Config, config.DB, config.NoSQLDB, config.Log); secondary != nil {
+			blogUsecase = blogUsecase.WithShadowReads(primary, secondary, shadowReader)
+		}
+	}
+
+	blogUsecase = blogUsecase.WithSearch(repositories.BlogSearch, repositories.Follow).
+		WithSearchIndexRebuilder(repositories.SearchIndexRebuilder).WithTags(repositories.BlogTag)
 
 	blogHandler := rest.NewBlogHandler(blogUsecase, config.Log)
 
+	tagHandler := rest.NewTagHandler(NewTagUseCase(config.Config, config.Log, repositories.BlogTag, repositories.Follow), config.Log)
+
+	// fill the embedded search index from Cassandra
+	if repositories.SearchIndexRebuilder != nil {
+		go repositories.SearchIndexRebuilder.Run(config.Context)
+	}
+
+	// remove expired blogs from Postgres and take them off the counts of every store
+	for _, sweeper := range repositories.ExpirySweepers {
+		go sweeper.Run(config.Context)
+	}
+
 	genericHandler := rest.NewGenericHandler(config.Log)
 
+	// there is nothing to reconcile with a single in-process store
+	var reconcileHandler *rest.ReconcileHandler
+	if !memory {
+		reconcileHandler = rest.NewReconcileHandler(NewReconcileUseCase(config.Config, config.DB, config.NoSQLDB, config.Log), config.Log)
+	}
+
 	// setup handler
-	apiHandler := rest.NewAPIHandler(genericHandler, userHandler, blogHandler)
+	apiHandler := rest.NewAPIHandler(genericHandler, userHandler, blogHandler, timelineHandler, tagHandler)
 
 	// setup middleware
 	authMiddleware := middleware.NewAuth(userUseCase, config.Log)
+	consistencyMiddleware := NewConsistencyMiddleware(config.Config, config.Log)
+	var tracingMiddleware fiber.Handler
+	if !memory {
+		tracingMiddleware = NewQueryTracingMiddleware(config.Config, config.Log, config.NoSQLDB.Session)
+	}
 
 	routerConfig := router.RouterConfig{
-		App:            config.App,
-		Log:            config.Log,
-		APIHandler:     *apiHandler,
-		AuthMiddleware: authMiddleware,
+		App:                   config.App,
+		Log:                   config.Log,
+		APIHandler:            *apiHandler,
+		AuthMiddleware:        authMiddleware,
+		ConsistencyMiddleware: consistencyMiddleware,
+		TracingMiddleware:     tracingMiddleware,
+		MetricsHandler:        rest.NewMetricsHandler(config.Recorder, config.Log),
+		OutboxHandler:         outboxHandler,
+		ReconcileHandler:      reconcileHandler,
+		ShadowHandler:         shadowHandler,
 	}
 	routerConfig.Setup()
+
+	return &background
 }
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/telemetry"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// NewBlogCountRepository builds the blog count repository of the stores behind storage.blogs
+func NewBlogCountRepository(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession, log *logrus.Logger) usecase.IBlogCountRepo {
+	storage := GetStorage(viper, "blogs", StoragePostgres)
+
+	switch storage {
+	case StoragePostgres:
+		return repository.NewBlogCountRepository(db, log)
+	case StorageCassandra:
+		return repository.NewBlogCountRepositoryNoSQL(noSQLDB, log)
+	case StorageDual:
+		return repository.NewBlogCountRepositoryDual(repository.NewBlogCountRepository(db, log), repository.NewBlogCountRepositoryNoSQL(noSQLDB, log), log)
+	default:
+		log.Fatalf("Unknown storage.blogs backend: %s", storage)
+		return nil
+	}
+}
+
+// NewBlogCountRepairs builds one count repair per store keeping blogs
+func NewBlogCountRepairs(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession, log *logrus.Logger,
+	config usecase.BlogCountRepairConfig) []usecase.BlogCountRepairUseCase {
+	storage := GetStorage(viper, "blogs", StoragePostgres)
+
+	var repairs []usecase.BlogCountRepairUseCase
+	if storage == StoragePostgres || storage == StorageDual {
+		repairs = append(repairs, usecase.NewBlogCountRepairUseCase(log, telemetry.StorePostgres,
+			repository.NewUserRepository(db, log),
+			repository.NewBlogRepository(db, log),
+			repository.NewBlogCountRepository(db, log),
+			config))
+	}
+	if storage == StorageCassandra || storage == StorageDual {
+		var users usecase.IUserPageRepo = repository.NewUserRepository(db, log)
+		if GetStorage(viper, "users", StorageDual) == StorageCassandra {
+			users = repository.NewUserRepositoryNoSQL(noSQLDB, log)
+		}
+		repairs = append(repairs, usecase.NewBlogCountRepairUseCase(log, telemetry.StoreCassandra,
+			users,
+			NewBlogRepositoryNoSQL(viper, noSQLDB, log),
+			repository.NewBlogCountRepositoryNoSQL(noSQLDB, log),
+			config))
+	}
+	if len(repairs) == 0 {
+		log.Fatalf("Unknown storage.blogs backend: %s", storage)
+	}
+
+	return repairs
+}
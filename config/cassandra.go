@@ -1,28 +1,281 @@
 package config
 
 import (
+	"strings"
+	"time"
+
 	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rifkiadrn/cassandra-explore/internal/handler/rest/middleware"
+	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/telemetry"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
-func NewNoSQLDatabase(viper *viper.Viper, log *logrus.Logger) *gocql.Session {
-	viper.AutomaticEnv()
+// Host selection policies for database.cassandra_host_selection
+const (
+	HostSelectionRoundRobin        = "round_robin"
+	HostSelectionTokenAware        = "token_aware"
+	HostSelectionDCAware           = "dc_aware"
+	HostSelectionTokenAwareDCAware = "token_aware_dc_aware"
+)
+
+// Retry policies for database.cassandra_retry.policy
+const (
+	RetryNone        = "none"
+	RetryExponential = "exponential"
+	RetryDowngrading = "downgrading" // Exponential backoff, each retry at the next level of downgrade_consistency
+)
 
+// CassandraKeyspace reads database.cassandra_keyspace with the DB_KEYSPACE env override
+func CassandraKeyspace(viper *viper.Viper) string {
 	databaseKeyspace := viper.GetString("database.cassandra_keyspace")
 	if viper.GetString("DB_KEYSPACE") != "" {
 		databaseKeyspace = viper.GetString("DB_KEYSPACE")
 	}
+	return databaseKeyspace
+}
 
-	cluster := gocql.NewCluster("cassandra-seed", "cassandra-node2", "cassandra-node3")
-	cluster.Port = 9042
-	cluster.Keyspace = databaseKeyspace
-	cluster.Consistency = gocql.Quorum
+// NewNoSQLDatabase opens the Cassandra session, recording statement latencies in recorder unless it is nil
+func NewNoSQLDatabase(viper *viper.Viper, log *logrus.Logger, recorder *telemetry.Recorder) repository.NoSQLSession {
+	session, err := OpenNoSQLDatabase(viper, log, recorder)
+	if err != nil {
+		log.Fatalf("Fatal error cassandra setup: %v", err)
+	}
+	return session
+}
+
+// OpenNoSQLDatabase is NewNoSQLDatabase returning the error instead of exiting
+func OpenNoSQLDatabase(viper *viper.Viper, log *logrus.Logger, recorder *telemetry.Recorder) (repository.NoSQLSession, error) {
+	cluster := NewNoSQLCluster(viper, log)
+	if recorder != nil {
+		observer := telemetry.NewCassandraObserver(recorder, log)
+		cluster.QueryObserver = observer
+		cluster.BatchObserver = observer
+	}
 
 	session, err := cluster.CreateSession()
 	if err != nil {
-		log.Fatalf("Fatal error cassandra setup: %v", err)
+		return repository.NoSQLSession{}, err
 	}
 
-	return session
+	return repository.NewNoSQLSession(session, NewSpeculativeExecution(viper, log)), nil
+}
+
+// NewNoSQLCluster builds the gocql cluster config from database.cassandra_* keys
+func NewNoSQLCluster(viper *viper.Viper, log *logrus.Logger) *gocql.ClusterConfig {
+	viper.AutomaticEnv()
+	viper.SetDefault("database.cassandra_hosts", []string{"cassandra-seed"})
+	viper.SetDefault("database.cassandra_port", 9042)
+	viper.SetDefault("database.cassandra_consistency", "quorum")
+	viper.SetDefault("database.cassandra_serial_consistency", "serial")
+	viper.SetDefault("database.cassandra_timeout_ms", 11000)
+	viper.SetDefault("database.cassandra_connect_timeout_ms", 11000)
+	viper.SetDefault("database.cassandra_num_conns", 2)
+	viper.SetDefault("database.cassandra_host_selection", HostSelectionRoundRobin)
+	viper.SetDefault("database.cassandra_retry.policy", RetryExponential)
+	viper.SetDefault("database.cassandra_retry.num_retries", 3)
+	viper.SetDefault("database.cassandra_retry.min_backoff_ms", 100)
+	viper.SetDefault("database.cassandra_retry.max_backoff_ms", 2000)
+
+	databaseKeyspace := CassandraKeyspace(viper)
+
+	// hosts may carry their own port, e.g. cassandra-seed:9042
+	hosts := viper.GetStringSlice("database.cassandra_hosts")
+	if viper.GetString("DB_CASSANDRA_HOSTS") != "" {
+		hosts = strings.Split(viper.GetString("DB_CASSANDRA_HOSTS"), ",")
+	}
+
+	cluster := gocql.NewCluster(hosts...)
+	cluster.Keyspace = databaseKeyspace
+	cluster.Port = envOrInt(viper, "database.cassandra_port", "DB_CASSANDRA_PORT")
+	cluster.Timeout = time.Millisecond * time.Duration(envOrInt(viper, "database.cassandra_timeout_ms", "DB_CASSANDRA_TIMEOUT_MS"))
+	cluster.ConnectTimeout = time.Millisecond * time.Duration(envOrInt(viper, "database.cassandra_connect_timeout_ms", "DB_CASSANDRA_CONNECT_TIMEOUT_MS"))
+	cluster.NumConns = envOrInt(viper, "database.cassandra_num_conns", "DB_CASSANDRA_NUM_CONNS")
+	// 0 lets the driver discover the protocol version
+	cluster.ProtoVersion = envOrInt(viper, "database.cassandra_proto_version", "DB_CASSANDRA_PROTO_VERSION")
+
+	consistency, err := gocql.ParseConsistencyWrapper(envOrString(viper, "database.cassandra_consistency", "DB_CASSANDRA_CONSISTENCY"))
+	if err != nil {
+		log.Fatalf("Invalid cassandra consistency: %v", err)
+	}
+	cluster.Consistency = consistency
+
+	serialConsistency, err := gocql.ParseConsistencyWrapper(envOrString(viper, "database.cassandra_serial_consistency", "DB_CASSANDRA_SERIAL_CONSISTENCY"))
+	if err != nil || (serialConsistency != gocql.Serial && serialConsistency != gocql.LocalSerial) {
+		log.Fatalf("Invalid cassandra serial consistency, expected serial or local_serial: %v", err)
+	}
+	cluster.SerialConsistency = serialConsistency
+
+	username := envOrString(viper, "database.cassandra_username", "DB_CASSANDRA_USERNAME")
+	if username != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{
+			Username: username,
+			Password: envOrString(viper, "database.cassandra_password", "DB_CASSANDRA_PASSWORD"),
+		}
+	}
+
+	if viper.GetBool("database.cassandra_tls.enabled") || viper.GetBool("DB_CASSANDRA_TLS_ENABLED") {
+		cluster.SslOpts = &gocql.SslOptions{
+			CaPath:                 envOrString(viper, "database.cassandra_tls.ca_path", "DB_CASSANDRA_TLS_CA_PATH"),
+			CertPath:               envOrString(viper, "database.cassandra_tls.cert_path", "DB_CASSANDRA_TLS_CERT_PATH"),
+			KeyPath:                envOrString(viper, "database.cassandra_tls.key_path", "DB_CASSANDRA_TLS_KEY_PATH"),
+			EnableHostVerification: viper.GetBool("database.cassandra_tls.host_verification"),
+		}
+	}
+
+	localDC := envOrString(viper, "database.cassandra_local_dc", "DB_CASSANDRA_LOCAL_DC")
+	hostSelection := envOrString(viper, "database.cassandra_host_selection", "DB_CASSANDRA_HOST_SELECTION")
+	switch hostSelection {
+	case HostSelectionRoundRobin:
+		cluster.PoolConfig.HostSelectionPolicy = gocql.RoundRobinHostPolicy()
+	case HostSelectionTokenAware:
+		cluster.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(gocql.RoundRobinHostPolicy())
+	case HostSelectionDCAware:
+		cluster.PoolConfig.HostSelectionPolicy = gocql.DCAwareRoundRobinPolicy(localDC)
+	case HostSelectionTokenAwareDCAware:
+		cluster.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(gocql.DCAwareRoundRobinPolicy(localDC))
+	default:
+		log.Fatalf("Unknown cassandra host selection policy: %s", hostSelection)
+	}
+	if (hostSelection == HostSelectionDCAware || hostSelection == HostSelectionTokenAwareDCAware) && localDC == "" {
+		log.Fatalf("database.cassandra_local_dc is required for the %s host selection policy", hostSelection)
+	}
+
+	cluster.RetryPolicy = newRetryPolicy(viper, log)
+
+	log.Infof("Cassandra hosts %v keyspace %s consistency %s host selection %s", hosts, databaseKeyspace, consistency, hostSelection)
+
+	return cluster
+}
+
+// newRetryPolicy builds the policy of database.cassandra_retry for idempotent statements
+func newRetryPolicy(viper *viper.Viper, log *logrus.Logger) gocql.RetryPolicy {
+	backoff := &gocql.ExponentialBackoffRetryPolicy{
+		NumRetries: envOrInt(viper, "database.cassandra_retry.num_retries", "DB_CASSANDRA_RETRY_NUM_RETRIES"),
+		Min:        time.Millisecond * time.Duration(envOrInt(viper, "database.cassandra_retry.min_backoff_ms", "DB_CASSANDRA_RETRY_MIN_BACKOFF_MS")),
+		Max:        time.Millisecond * time.Duration(envOrInt(viper, "database.cassandra_retry.max_backoff_ms", "DB_CASSANDRA_RETRY_MAX_BACKOFF_MS")),
+	}
+
+	policy := envOrString(viper, "database.cassandra_retry.policy", "DB_CASSANDRA_RETRY_POLICY")
+	switch policy {
+	case RetryNone:
+		return &gocql.SimpleRetryPolicy{NumRetries: 0}
+	case RetryExponential:
+		return backoff
+	case RetryDowngrading:
+		levels := viper.GetStringSlice("database.cassandra_retry.downgrade_consistency")
+		if viper.GetString("DB_CASSANDRA_RETRY_DOWNGRADE_CONSISTENCY") != "" {
+			levels = strings.Split(viper.GetString("DB_CASSANDRA_RETRY_DOWNGRADE_CONSISTENCY"), ",")
+		}
+		if len(levels) == 0 {
+			log.Fatalf("database.cassandra_retry.downgrade_consistency is required for the %s retry policy", policy)
+		}
+
+		downgrading := &gocql.DowngradingConsistencyRetryPolicy{}
+		for _, level := range levels {
+			consistency, err := gocql.ParseConsistencyWrapper(strings.ToUpper(strings.TrimSpace(level)))
+			if err != nil {
+				log.Fatalf("Invalid consistency %s in database.cassandra_retry.downgrade_consistency: %v", level, err)
+			}
+			downgrading.ConsistencyLevelsToTry = append(downgrading.ConsistencyLevelsToTry, consistency)
+		}
+		return &downgradingBackoffRetryPolicy{backoff: backoff, downgrading: downgrading}
+	default:
+		log.Fatalf("Unknown cassandra retry policy: %s", policy)
+	}
+	return nil
+}
+
+// downgradingBackoffRetryPolicy backs off exponentially and retries at the next lower consistency
+type downgradingBackoffRetryPolicy struct {
+	backoff     *gocql.ExponentialBackoffRetryPolicy
+	downgrading *gocql.DowngradingConsistencyRetryPolicy
+}
+
+func (p *downgradingBackoffRetryPolicy) Attempt(q gocql.RetryableQuery) bool {
+	return p.downgrading.Attempt(q) && p.backoff.Attempt(q)
+}
+
+func (p *downgradingBackoffRetryPolicy) GetRetryType(err error) gocql.RetryType {
+	return p.downgrading.GetRetryType(err)
+}
+
+// NewSpeculativeExecution builds the policy of database.cassandra_speculative for idempotent queries
+func NewSpeculativeExecution(viper *viper.Viper, log *logrus.Logger) gocql.SpeculativeExecutionPolicy {
+	attempts := envOrInt(viper, "database.cassandra_speculative.attempts", "DB_CASSANDRA_SPECULATIVE_ATTEMPTS")
+	if attempts <= 0 {
+		return &gocql.NonSpeculativeExecution{}
+	}
+
+	delay := time.Millisecond * time.Duration(envOrInt(viper, "database.cassandra_speculative.delay_ms", "DB_CASSANDRA_SPECULATIVE_DELAY_MS"))
+	if delay <= 0 {
+		log.Fatalf("database.cassandra_speculative.delay_ms must be positive")
+	}
+	log.Infof("Cassandra speculative execution %d attempts after %s", attempts, delay)
+
+	return &gocql.SimpleSpeculativeExecution{NumAttempts: attempts, TimeoutDelay: delay}
+}
+
+// NewConsistencyMiddleware allows the consistency overrides of database.cassandra_consistency_allowlist
+func NewConsistencyMiddleware(viper *viper.Viper, log *logrus.Logger) fiber.Handler {
+	levels := viper.GetStringSlice("database.cassandra_consistency_allowlist")
+	if viper.GetString("DB_CASSANDRA_CONSISTENCY_ALLOWLIST") != "" {
+		levels = strings.Split(viper.GetString("DB_CASSANDRA_CONSISTENCY_ALLOWLIST"), ",")
+	}
+	if len(levels) == 0 {
+		return nil
+	}
+
+	allowed := make([]gocql.Consistency, 0, len(levels))
+	for _, level := range levels {
+		consistency, err := gocql.ParseConsistencyWrapper(strings.ToUpper(strings.TrimSpace(level)))
+		if err != nil || consistency == gocql.Serial || consistency == gocql.LocalSerial {
+			log.Fatalf("Invalid consistency %s in database.cassandra_consistency_allowlist: %v", level, err)
+		}
+		allowed = append(allowed, consistency)
+	}
+
+	defaultConsistency, err := gocql.ParseConsistencyWrapper(envOrString(viper, "database.cassandra_consistency", "DB_CASSANDRA_CONSISTENCY"))
+	if err != nil {
+		log.Fatalf("Invalid cassandra consistency: %v", err)
+	}
+
+	return middleware.NewConsistency(allowed, defaultConsistency, log)
+}
+
+// NewQueryTracingMiddleware traces the Cassandra queries of tracing.sample_percent of the requests
+func NewQueryTracingMiddleware(viper *viper.Viper, log *logrus.Logger, session *gocql.Session) fiber.Handler {
+	samplePercent := viper.GetFloat64("tracing.sample_percent")
+	if viper.GetFloat64("TRACING_SAMPLE_PERCENT") != 0 {
+		samplePercent = viper.GetFloat64("TRACING_SAMPLE_PERCENT")
+	}
+	if samplePercent <= 0 {
+		return nil
+	}
+	if samplePercent > 100 {
+		log.Fatalf("tracing.sample_percent must be between 0 and 100: %v", samplePercent)
+	}
+
+	log.Infof("Tracing Cassandra queries of %v%% of the requests", samplePercent)
+	return middleware.NewQueryTracing(samplePercent, func(requestID string) gocql.Tracer {
+		return telemetry.NewTraceLogger(session, log, requestID)
+	})
+}
+
+// envOrString reads a config key, letting a non-empty env var win
+func envOrString(viper *viper.Viper, key string, env string) string {
+	if viper.GetString(env) != "" {
+		return viper.GetString(env)
+	}
+	return viper.GetString(key)
+}
+
+// envOrInt reads a config key, letting a non-zero env var win
+func envOrInt(viper *viper.Viper, key string, env string) int {
+	if viper.GetInt(env) != 0 {
+		return viper.GetInt(env)
+	}
+	return viper.GetInt(key)
 }
@@ -0,0 +1,20 @@
+package config
+
+import (
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/rifkiadrn/cassandra-explore/internal/migration"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func NewCQLMigrator(viper *viper.Viper, log *logrus.Logger) *migration.CQLMigrator {
+	viper.SetDefault("database.cassandra_migrations_dir", "./db/cql/migrations")
+	viper.SetDefault("database.cassandra_replication", "{'class': 'SimpleStrategy', 'replication_factor': 2}")
+
+	return migration.NewCQLMigrator(
+		func() *gocql.ClusterConfig { return NewNoSQLCluster(viper, log) },
+		envOrString(viper, "database.cassandra_migrations_dir", "DB_CASSANDRA_MIGRATIONS_DIR"),
+		envOrString(viper, "database.cassandra_replication", "DB_CASSANDRA_REPLICATION"),
+		log,
+	)
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"time"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// NewExpirySweeper builds the expiry sweeper of one blog store and its counts
+func NewExpirySweeper(viper *viper.Viper, uow usecase.UnitOfWork, log *logrus.Logger,
+	blogRepository usecase.IBlogExpiryRepo, countRepository usecase.IBlogCountRepo) *usecase.ExpirySweeper {
+	viper.SetDefault("expiry.sweep_interval_ms", 60000)
+	viper.SetDefault("expiry.batch_size", 1000)
+
+	return usecase.NewExpirySweeper(uow, log, blogRepository, countRepository, usecase.ExpirySweeperConfig{
+		Interval:  time.Millisecond * time.Duration(viper.GetInt("expiry.sweep_interval_ms")),
+		BatchSize: viper.GetInt("expiry.batch_size"),
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/rifkiadrn/cassandra-explore/internal/telemetry"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"gorm.io/driver/postgres"
@@ -11,7 +12,17 @@ import (
 	"gorm.io/gorm/logger"
 )
 
-func NewDatabase(viper *viper.Viper, log *logrus.Logger) *gorm.DB {
+// NewDatabase opens the Postgres pool, recording statement latencies in recorder unless it is nil
+func NewDatabase(viper *viper.Viper, log *logrus.Logger, recorder *telemetry.Recorder) *gorm.DB {
+	db, err := OpenDatabase(viper, log, recorder)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	return db
+}
+
+// OpenDatabase is NewDatabase returning the error instead of exiting, e.g. for tests
+func OpenDatabase(viper *viper.Viper, log *logrus.Logger, recorder *telemetry.Recorder) (*gorm.DB, error) {
 	viper.AutomaticEnv()
 	username := viper.GetString("database.username")
 	if viper.GetString("DB_USERNAME") != "" {
@@ -43,9 +54,11 @@ func NewDatabase(viper *viper.Viper, log *logrus.Logger) *gorm.DB {
 	maxLifeTimeConnection := viper.GetInt("database.pool.lifetime")
 
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=Asia/Jakarta", host, username, password, database, port)
-	log.Printf(dsn)
+	log.Print(dsn)
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		// unique violations surface as gorm.ErrDuplicatedKey
+		TranslateError: true,
 		Logger: logger.New(&logrusWriter{Logger: log}, logger.Config{
 			SlowThreshold:             time.Second * 5,
 			Colorful:                  false,
@@ -55,17 +68,23 @@ func NewDatabase(viper *viper.Viper, log *logrus.Logger) *gorm.DB {
 		}),
 	})
 	if err != nil {
-		log.Fatalf("failed to connect database: %v", err)
+		return nil, fmt.Errorf("failed to connect database: %w", err)
+	}
+
+	if recorder != nil {
+		if err := db.Use(telemetry.NewGormPlugin(recorder, log, fmt.Sprintf("%s:%d", host, port))); err != nil {
+			return nil, fmt.Errorf("failed to instrument database: %w", err)
+		}
 	}
 
 	connection, err := db.DB()
 	if err != nil {
-		log.Fatalf("failed to connect database: %v", err)
+		return nil, fmt.Errorf("failed to connect database: %w", err)
 	}
 
 	connection.SetMaxIdleConns(idleConnection)
 	connection.SetMaxOpenConns(maxConnection)
 	connection.SetConnMaxLifetime(time.Second * time.Duration(maxLifeTimeConnection))
 
-	return db
+	return db, nil
 }
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"time"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func NewOutboxRelay(viper *viper.Viper, uow usecase.UnitOfWork, log *logrus.Logger, outboxRepository usecase.IOutboxRepo) *usecase.OutboxRelay {
+	viper.SetDefault("outbox.poll_interval_ms", 1000)
+	viper.SetDefault("outbox.batch_size", 100)
+	viper.SetDefault("outbox.max_attempts", 10)
+	viper.SetDefault("outbox.backoff_base_ms", 1000)
+	viper.SetDefault("outbox.backoff_max_ms", 300000)
+	viper.SetDefault("outbox.lease_ms", 60000)
+
+	return usecase.NewOutboxRelay(uow, log, outboxRepository, usecase.OutboxRelayConfig{
+		PollInterval: time.Millisecond * time.Duration(viper.GetInt("outbox.poll_interval_ms")),
+		BatchSize:    viper.GetInt("outbox.batch_size"),
+		MaxAttempts:  viper.GetInt("outbox.max_attempts"),
+		BackoffBase:  time.Millisecond * time.Duration(viper.GetInt("outbox.backoff_base_ms")),
+		BackoffMax:   time.Millisecond * time.Duration(viper.GetInt("outbox.backoff_max_ms")),
+		Lease:        time.Millisecond * time.Duration(viper.GetInt("outbox.lease_ms")),
+	})
+}
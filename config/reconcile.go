@@ -0,0 +1,35 @@
+package config
+
+import (
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// NewReconcileUseCase builds the reconciler over both stores
+func NewReconcileUseCase(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession, log *logrus.Logger) usecase.ReconcileUseCase {
+	viper.SetDefault("reconcile.batch_size", 500)
+	viper.SetDefault("reconcile.repair", entity.RepairNone)
+	viper.SetDefault("reconcile.max_diffs", 1000)
+
+	repair := viper.GetString("reconcile.repair")
+	switch repair {
+	case entity.RepairNone, entity.RepairPostgresToCassandra, entity.RepairCassandraToPostgres:
+	default:
+		log.Fatalf("Unknown reconcile.repair direction: %s", repair)
+	}
+
+	return usecase.NewReconcileUseCase(log,
+		repository.NewUserRepository(db, log),
+		repository.NewUserRepositoryNoSQL(noSQLDB, log),
+		repository.NewBlogRepository(db, log),
+		NewBlogRepositoryNoSQL(viper, noSQLDB, log),
+		usecase.ReconcileConfig{
+			BatchSize: viper.GetInt("reconcile.batch_size"),
+			Repair:    repair,
+			MaxDiffs:  viper.GetInt("reconcile.max_diffs"),
+		})
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// NewBlogSearch builds blog search for storage.blogs. Cassandra blogs are only searched through the
+// in-process index of search.embedded_index, the search repository is nil otherwise.
+func NewBlogSearch(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession, log *logrus.Logger,
+	blogRepository usecase.IBlog) (usecase.IBlog, usecase.IBlogSearchRepo, *usecase.SearchIndexRebuilder) {
+	if GetStorage(viper, "blogs", StoragePostgres) != StorageCassandra {
+		return blogRepository, repository.NewBlogRepository(db, log), nil
+	}
+
+	viper.SetDefault("search.rebuild_batch_size", 500)
+	viper.SetDefault("search.embedded_index", false)
+
+	if !viper.GetBool("search.embedded_index") {
+		log.Warn("Blog search is off: Cassandra cannot search blogs and search.embedded_index is not set")
+		return blogRepository, nil, nil
+	}
+	log.Warn("search.embedded_index is set: the blog search index lives in this process and every instance only " +
+		"indexes the blogs written through it, run a single instance. Searches fail until the index is rebuilt from Cassandra.")
+
+	index := repository.NewSearchIndexMemory()
+	indexed := repository.NewBlogRepositoryIndexed(blogRepository, index, log)
+	rebuilder := usecase.NewSearchIndexRebuilder(log, NewBlogRepositoryNoSQL(viper, noSQLDB, log), index,
+		usecase.SearchIndexRebuilderConfig{
+			BatchSize: viper.GetInt("search.rebuild_batch_size"),
+		},
+	)
+
+	return indexed, indexed, &rebuilder
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"time"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// NewShadowReader returns nil unless shadow.sample_percent is set
+func NewShadowReader(viper *viper.Viper, log *logrus.Logger) *usecase.ShadowReader {
+	viper.SetDefault("shadow.timeout_ms", 2000)
+
+	samplePercent := viper.GetFloat64("shadow.sample_percent")
+	if samplePercent <= 0 {
+		return nil
+	}
+	if samplePercent > 100 {
+		log.Fatalf("shadow.sample_percent must be between 0 and 100: %v", samplePercent)
+	}
+
+	return usecase.NewShadowReader(log, usecase.ShadowConfig{
+		SamplePercent: samplePercent,
+		Timeout:       time.Millisecond * time.Duration(viper.GetInt("shadow.timeout_ms")),
+	})
+}
+
+// shadowPrimary reads shadow.primary, the store answering shadowed reads
+func shadowPrimary(viper *viper.Viper, log *logrus.Logger) string {
+	viper.SetDefault("shadow.primary", StoragePostgres)
+
+	primary := viper.GetString("shadow.primary")
+	if primary != StoragePostgres && primary != StorageCassandra {
+		log.Fatalf("Unknown shadow.primary store: %s", primary)
+	}
+	return primary
+}
+
+// NewShadowUserRepositories returns the user stores of dual storage.users, else nil
+func NewShadowUserRepositories(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession, log *logrus.Logger) (usecase.IUserRepo, usecase.IUserRepo) {
+	if GetStorage(viper, "users", StorageDual) != StorageDual {
+		return nil, nil
+	}
+
+	postgres := repository.NewUserRepository(db, log)
+	cassandra := repository.NewUserRepositoryNoSQL(noSQLDB, log)
+	if shadowPrimary(viper, log) == StorageCassandra {
+		return cassandra, postgres
+	}
+	return postgres, cassandra
+}
+
+// NewShadowBlogRepositories returns the blog stores of dual storage.blogs, else nil
+func NewShadowBlogRepositories(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession, log *logrus.Logger) (usecase.IBlog, usecase.IBlog) {
+	if GetStorage(viper, "blogs", StoragePostgres) != StorageDual {
+		return nil, nil
+	}
+
+	postgres := repository.NewBlogRepository(db, log)
+	cassandra := NewBlogRepositoryNoSQL(viper, noSQLDB, log)
+	if shadowPrimary(viper, log) == StorageCassandra {
+		return cassandra, postgres
+	}
+	return postgres, cassandra
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"strings"
+
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// Storage backends selectable per resource with the storage.* config keys
+const (
+	StoragePostgres  = "postgres"
+	StorageCassandra = "cassandra"
+	StorageDual      = "dual"
+	StorageMemory    = "memory" // storage.mode only, everything in process
+)
+
+// GetStorage reads the backend for a resource, e.g. storage.blogs, with an env override such as STORAGE_BLOGS
+func GetStorage(viper *viper.Viper, resource string, fallback string) string {
+	viper.AutomaticEnv()
+
+	storage := viper.GetString("storage." + resource)
+	if env := viper.GetString("STORAGE_" + strings.ToUpper(resource)); env != "" {
+		storage = env
+	}
+	if storage == "" {
+		storage = fallback
+	}
+
+	return storage
+}
+
+// IsMemoryStorage tells whether storage.mode, or STORAGE_MODE, keeps everything in process
+func IsMemoryStorage(viper *viper.Viper) bool {
+	return GetStorage(viper, "mode", "") == StorageMemory
+}
+
+// Repositories are the stores the HTTP API is served from
+type Repositories struct {
+	User                 usecase.IUserRepo
+	UserNoSQL            usecase.IUserRepoNoSQL // The Cassandra copy written on register, nil when there is no copy to keep
+	Blog                 usecase.IBlog
+	BlogCount            usecase.IBlogCountRepo
+	BlogRevision         usecase.IBlogRevisionRepo
+	BlogSearch           usecase.IBlogSearchRepo
+	BlogTag              usecase.IBlogTagRepo
+	Follow               usecase.IFollowRepo   // Cassandra whatever storage.* selects, see NewRepositories
+	Timeline             usecase.ITimelineRepo // Cassandra whatever storage.* selects, see NewRepositories
+	UnitOfWork           usecase.UnitOfWork
+	UserUnitOfWork       usecase.UnitOfWork
+	BlogUnitOfWork       usecase.UnitOfWork
+	ExpirySweepers       []*usecase.ExpirySweeper      // One per store keeping blogs
+	SearchIndexRebuilder *usecase.SearchIndexRebuilder // nil unless blogs are searched through an embedded index
+}
+
+// NewRepositories builds the repositories selected by the storage.* config.
+// Follows and timelines only live in Cassandra, so it is always needed.
+func NewRepositories(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession, log *logrus.Logger) Repositories {
+	userRepository, userRepositoryNoSQL := NewUserRepositories(viper, db, noSQLDB, log)
+	unitOfWork := context_db.NewGormUnitOfWork(db)
+	blogRepository, blogSearch, searchIndexRebuilder := NewBlogSearch(viper, db, noSQLDB, log, NewBlogRepository(viper, db, noSQLDB, log))
+
+	repositories := Repositories{
+		User:                 userRepository,
+		UserNoSQL:            userRepositoryNoSQL,
+		Blog:                 blogRepository,
+		BlogCount:            NewBlogCountRepository(viper, db, noSQLDB, log),
+		BlogRevision:         NewBlogRevisionRepository(viper, db, noSQLDB, log),
+		BlogSearch:           blogSearch,
+		BlogTag:              NewBlogTagRepository(viper, db, noSQLDB, log),
+		Follow:               repository.NewFollowRepositoryNoSQL(noSQLDB),
+		Timeline:             repository.NewTimelineRepositoryNoSQL(noSQLDB),
+		UnitOfWork:           unitOfWork,
+		UserUnitOfWork:       NewUserUnitOfWork(viper, db, noSQLDB),
+		BlogUnitOfWork:       NewBlogUnitOfWork(viper, db, noSQLDB),
+		SearchIndexRebuilder: searchIndexRebuilder,
+	}
+
+	// Cassandra expires blogs with a TTL, only its counts are swept
+	storage := GetStorage(viper, "blogs", StoragePostgres)
+	if storage != StorageCassandra {
+		repositories.ExpirySweepers = append(repositories.ExpirySweepers, NewExpirySweeper(viper, unitOfWork, log,
+			repository.NewBlogRepository(db, log), repository.NewBlogCountRepository(db, log)))
+	}
+	if storage != StoragePostgres {
+		repositories.ExpirySweepers = append(repositories.ExpirySweepers, NewExpirySweeper(viper, context_db.NewCassandraUnitOfWork(noSQLDB.Session), log,
+			NewBlogRepositoryNoSQL(viper, noSQLDB, log), repository.NewBlogCountRepositoryNoSQL(noSQLDB, log)))
+	}
+
+	return repositories
+}
+
+// NewMemoryRepositories builds in-process repositories sharing one store, nothing survives a restart
+func NewMemoryRepositories(viper *viper.Viper, log *logrus.Logger) Repositories {
+	store := context_db.NewMemoryStore()
+	unitOfWork := context_db.NewMemoryUnitOfWork(store)
+	blogRepository := repository.NewBlogRepositoryMemory(store)
+	blogCountRepository := repository.NewBlogCountRepositoryMemory(store)
+	// searched through the embedded index Cassandra blogs use
+	indexedBlogRepository := repository.NewBlogRepositoryIndexed(blogRepository, repository.NewSearchIndexMemory(), log)
+
+	return Repositories{
+		User:           repository.NewUserRepositoryMemory(store),
+		Blog:           indexedBlogRepository,
+		BlogCount:      blogCountRepository,
+		BlogRevision:   repository.NewBlogRevisionRepositoryMemory(store),
+		BlogSearch:     indexedBlogRepository,
+		BlogTag:        repository.NewBlogTagRepositoryMemory(store),
+		Follow:         repository.NewFollowRepositoryMemory(store),
+		Timeline:       repository.NewTimelineRepositoryMemory(store),
+		UnitOfWork:     unitOfWork,
+		UserUnitOfWork: unitOfWork,
+		BlogUnitOfWork: unitOfWork,
+		ExpirySweepers: []*usecase.ExpirySweeper{NewExpirySweeper(viper, unitOfWork, log, blogRepository, blogCountRepository)},
+	}
+}
+
+// NewUserRepositories builds the user repositories for storage.users, the second is the Cassandra copy or nil
+func NewUserRepositories(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession, log *logrus.Logger) (usecase.IUserRepo, usecase.IUserRepoNoSQL) {
+	storage := GetStorage(viper, "users", StorageDual)
+
+	switch storage {
+	case StoragePostgres:
+		return repository.NewUserRepository(db, log), nil
+	case StorageCassandra:
+		return repository.NewUserRepositoryNoSQL(noSQLDB, log), nil
+	case StorageDual:
+		return repository.NewUserRepository(db, log), repository.NewUserRepositoryNoSQL(noSQLDB, log)
+	default:
+		log.Fatalf("Unknown storage.users backend: %s", storage)
+		return nil, nil
+	}
+}
+
+// NewBlogRepository builds the blog repository for the configured storage.blogs backend
+func NewBlogRepository(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession, log *logrus.Logger) usecase.IBlog {
+	storage := GetStorage(viper, "blogs", StoragePostgres)
+
+	switch storage {
+	case StoragePostgres:
+		return repository.NewBlogRepository(db, log)
+	case StorageCassandra:
+		return NewBlogRepositoryNoSQL(viper, noSQLDB, log)
+	case StorageDual:
+		return repository.NewBlogRepositoryDual(repository.NewBlogRepository(db, log), NewBlogRepositoryNoSQL(viper, noSQLDB, log), log)
+	default:
+		log.Fatalf("Unknown storage.blogs backend: %s", storage)
+		return nil
+	}
+}
+
+// NewBlogRevisionRepository builds the blog revision repository for the configured storage.blogs backend
+func NewBlogRevisionRepository(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession, log *logrus.Logger) usecase.IBlogRevisionRepo {
+	storage := GetStorage(viper, "blogs", StoragePostgres)
+
+	switch storage {
+	case StoragePostgres:
+		return repository.NewBlogRevisionRepository(db, log)
+	case StorageCassandra:
+		return repository.NewBlogRevisionRepositoryNoSQL(noSQLDB)
+	case StorageDual:
+		return repository.NewBlogRevisionRepositoryDual(repository.NewBlogRevisionRepository(db, log), repository.NewBlogRevisionRepositoryNoSQL(noSQLDB), log)
+	default:
+		log.Fatalf("Unknown storage.blogs backend: %s", storage)
+		return nil
+	}
+}
+
+// NewBlogTagRepository builds the blog tag repository for the configured storage.blogs backend
+func NewBlogTagRepository(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession, log *logrus.Logger) usecase.IBlogTagRepo {
+	storage := GetStorage(viper, "blogs", StoragePostgres)
+
+	switch storage {
+	case StoragePostgres:
+		return repository.NewBlogTagRepository(db, log)
+	case StorageCassandra:
+		return repository.NewBlogTagRepositoryNoSQL(noSQLDB, NewBlogBucket(viper, log), log)
+	case StorageDual:
+		return repository.NewBlogTagRepositoryDual(repository.NewBlogTagRepository(db, log), repository.NewBlogTagRepositoryNoSQL(noSQLDB, NewBlogBucket(viper, log), log), log)
+	default:
+		log.Fatalf("Unknown storage.blogs backend: %s", storage)
+		return nil
+	}
+}
+
+// NewUserUnitOfWork builds the unit of work for the store behind storage.users
+func NewUserUnitOfWork(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession) usecase.UnitOfWork {
+	if GetStorage(viper, "users", StorageDual) == StorageCassandra {
+		return context_db.NewCassandraUnitOfWork(noSQLDB.Session)
+	}
+	return context_db.NewGormUnitOfWork(db)
+}
+
+// NewBlogUnitOfWork builds the unit of work for the store behind storage.blogs
+func NewBlogUnitOfWork(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession) usecase.UnitOfWork {
+	if GetStorage(viper, "blogs", StoragePostgres) == StorageCassandra {
+		return context_db.NewCassandraUnitOfWork(noSQLDB.Session)
+	}
+	return context_db.NewGormUnitOfWork(db)
+}
+
+// NewBlogRepositoryNoSQL builds the Cassandra blog repository
+func NewBlogRepositoryNoSQL(viper *viper.Viper, noSQLDB repository.NoSQLSession, log *logrus.Logger) repository.BlogRepositoryNoSQL {
+	return repository.NewBlogRepositoryNoSQL(noSQLDB, NewBlogBucket(viper, log))
+}
+
+// NewBlogBucket reads the database.cassandra_blog_bucket partition size
+func NewBlogBucket(viper *viper.Viper, log *logrus.Logger) string {
+	viper.SetDefault("database.cassandra_blog_bucket", repository.BucketMonth)
+
+	bucket := envOrString(viper, "database.cassandra_blog_bucket", "DB_CASSANDRA_BLOG_BUCKET")
+	switch bucket {
+	case repository.BucketMonth, repository.BucketWeek:
+	default:
+		log.Fatalf("Unknown database.cassandra_blog_bucket: %s", bucket)
+	}
+
+	return bucket
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"time"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// NewTagUseCase builds the tag listings from tags.* config
+func NewTagUseCase(viper *viper.Viper, log *logrus.Logger, tagRepository usecase.IBlogTagRepo, followRepository usecase.IFollowRepo) usecase.TagUseCase {
+	viper.SetDefault("tags.default_window_hours", 24)
+	viper.SetDefault("tags.max_window_hours", 720)
+
+	return usecase.NewTagUseCase(log, tagRepository, followRepository, usecase.TagConfig{
+		DefaultWindow: time.Hour * time.Duration(viper.GetInt("tags.default_window_hours")),
+		MaxWindow:     time.Hour * time.Duration(viper.GetInt("tags.max_window_hours")),
+	})
+}
+
+// NewRetagUseCase builds the retag of the store behind storage.blogs
+func NewRetagUseCase(viper *viper.Viper, db *gorm.DB, noSQLDB repository.NoSQLSession, log *logrus.Logger,
+	checkpointStore usecase.ICheckpointStore, config usecase.RetagConfig) usecase.RetagUseCase {
+	var blogRepository usecase.IBlogScanRepo = repository.NewBlogRepository(db, log)
+	if GetStorage(viper, "blogs", StoragePostgres) == StorageCassandra {
+		blogRepository = NewBlogRepositoryNoSQL(viper, noSQLDB, log)
+	}
+
+	return usecase.NewRetagUseCase(log, blogRepository, NewBlogTagRepository(viper, db, noSQLDB, log), checkpointStore, config)
+}
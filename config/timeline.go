@@ -0,0 +1,27 @@
+package config
+
+import (
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// NewTimelineUseCase builds the home timeline from timeline.* config
+func NewTimelineUseCase(viper *viper.Viper, log *logrus.Logger, userRepository usecase.IUserRepo, blogRepository usecase.IBlog,
+	followRepository usecase.IFollowRepo, timelineRepository usecase.ITimelineRepo) usecase.TimelineUseCase {
+	viper.SetDefault("timeline.fanout_threshold", 10000)
+	viper.SetDefault("timeline.fanout_batch_size", 500)
+	viper.SetDefault("timeline.fanout_concurrency", 16)
+	viper.SetDefault("timeline.fanout_workers", 4)
+	viper.SetDefault("timeline.fanout_queue_size", 1000)
+
+	return usecase.NewTimelineUseCase(log, userRepository, blogRepository, followRepository, timelineRepository,
+		usecase.TimelineConfig{
+			FanoutThreshold:   viper.GetInt64("timeline.fanout_threshold"),
+			FanoutBatchSize:   viper.GetInt("timeline.fanout_batch_size"),
+			FanoutConcurrency: viper.GetInt("timeline.fanout_concurrency"),
+			FanoutWorkers:     viper.GetInt("timeline.fanout_workers"),
+			FanoutQueueSize:   viper.GetInt("timeline.fanout_queue_size"),
+		},
+	)
+}
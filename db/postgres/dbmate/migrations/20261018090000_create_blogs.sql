@@ -0,0 +1,15 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS blogs (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    content TEXT NOT NULL,
+    user_id UUID NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    ts BIGINT NOT NULL DEFAULT DATE_PART('EPOCH', NOW())
+);
+
+-- keyset pagination on (ts, id) within an author
+CREATE INDEX IF NOT EXISTS blogs_user_id_ts_id_idx ON blogs (user_id, ts DESC, id DESC);
+
+-- migrate:down
+DROP INDEX IF EXISTS blogs_user_id_ts_id_idx;
+DROP TABLE IF EXISTS blogs;
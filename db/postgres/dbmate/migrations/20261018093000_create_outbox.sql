@@ -0,0 +1,19 @@
+-- migrate:up
+CREATE TABLE cassandra_users.outbox (
+    id UUID NOT NULL PRIMARY KEY DEFAULT uuid_generate_v4(),
+    aggregate_id UUID NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at BIGINT NOT NULL DEFAULT DATE_PART('EPOCH', NOW()),
+    created_at BIGINT NOT NULL DEFAULT DATE_PART('EPOCH', NOW()),
+    updated_at BIGINT NOT NULL DEFAULT DATE_PART('EPOCH', NOW())
+);
+
+-- the relay only ever scans pending rows that are due
+CREATE INDEX outbox_pending_idx ON cassandra_users.outbox (next_attempt_at, created_at) WHERE status = 'pending';
+
+-- migrate:down
+DROP TABLE IF EXISTS cassandra_users.outbox;
@@ -0,0 +1,9 @@
+-- migrate:up
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS expires_at BIGINT;
+
+-- the expiry sweeper only looks at blogs that expire
+CREATE INDEX IF NOT EXISTS blogs_expires_at_idx ON blogs (expires_at) WHERE expires_at IS NOT NULL;
+
+-- migrate:down
+DROP INDEX IF EXISTS blogs_expires_at_idx;
+ALTER TABLE blogs DROP COLUMN IF EXISTS expires_at;
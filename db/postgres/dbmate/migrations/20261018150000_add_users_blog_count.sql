@@ -0,0 +1,7 @@
+-- migrate:up
+ALTER TABLE cassandra_users.users ADD COLUMN IF NOT EXISTS blog_count BIGINT NOT NULL DEFAULT 0;
+
+UPDATE cassandra_users.users u SET blog_count = (SELECT COUNT(*) FROM blogs b WHERE b.user_id = u.id);
+
+-- migrate:down
+ALTER TABLE cassandra_users.users DROP COLUMN IF EXISTS blog_count;
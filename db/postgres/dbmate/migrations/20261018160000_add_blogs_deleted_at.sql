@@ -0,0 +1,6 @@
+-- migrate:up
+-- deleted blogs are kept with their deletion time, every read skips them
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS deleted_at BIGINT;
+
+-- migrate:down
+ALTER TABLE blogs DROP COLUMN IF EXISTS deleted_at;
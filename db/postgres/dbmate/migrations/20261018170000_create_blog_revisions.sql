@@ -0,0 +1,16 @@
+-- migrate:up
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS revision INT NOT NULL DEFAULT 1;
+
+-- the content of a blog before each edit, swept with its blog when it expires
+CREATE TABLE IF NOT EXISTS blog_revisions (
+    blog_id UUID NOT NULL REFERENCES blogs (id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    content TEXT NOT NULL,
+    edited_at BIGINT NOT NULL,
+    expires_at BIGINT,
+    PRIMARY KEY (blog_id, revision)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS blog_revisions;
+ALTER TABLE blogs DROP COLUMN IF EXISTS revision;
@@ -0,0 +1,10 @@
+-- migrate:up
+-- words of the content for search, the simple configuration lowercases without stemming or stop words,
+-- the way the embedded index used with Cassandra splits words
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS search tsvector GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED;
+
+CREATE INDEX IF NOT EXISTS blogs_search_idx ON blogs USING GIN (search);
+
+-- migrate:down
+DROP INDEX IF EXISTS blogs_search_idx;
+ALTER TABLE blogs DROP COLUMN IF EXISTS search;
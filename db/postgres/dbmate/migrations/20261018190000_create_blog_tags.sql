@@ -0,0 +1,17 @@
+-- migrate:up
+-- the #tags of each blog with its time, swept with its blog when it expires
+CREATE TABLE IF NOT EXISTS blog_tags (
+    blog_id UUID NOT NULL REFERENCES blogs (id) ON DELETE CASCADE,
+    tag VARCHAR(64) NOT NULL,
+    ts BIGINT NOT NULL,
+    PRIMARY KEY (blog_id, tag)
+);
+
+-- keyset pagination on (ts, blog_id) within a tag
+CREATE INDEX IF NOT EXISTS blog_tags_tag_ts_blog_id_idx ON blog_tags (tag, ts DESC, blog_id DESC);
+
+-- the tags used in a time window
+CREATE INDEX IF NOT EXISTS blog_tags_ts_idx ON blog_tags (ts);
+
+-- migrate:down
+DROP TABLE IF EXISTS blog_tags;
@@ -7,6 +7,9 @@ dbmate --migrations-dir $DBMATE_DEFAULT_MIGRATIONS_DIR --migrations-table $DBMAT
 if [ "$AUTO_MIGRATE_POSTDEPLOYMENT_MIGRATIONS" = "true" ]; then
     dbmate --migrations-dir $DBMATE_POSTDEPLOYMENT_MIGRATIONS_DIR --migrations-table $DBMATE_POSTDEPLOYMENT_MIGRATIONS_TABLE migrate
 fi
+if [ "$AUTO_MIGRATE_CQL_MIGRATIONS" = "true" ]; then
+    ./cqlmigrate up
+fi
 
 # Debugging: Check if the file exists and print out the path
 if [ -f "/app/app" ]; then
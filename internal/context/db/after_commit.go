@@ -0,0 +1,73 @@
+package context_db
+
+import (
+	"context"
+	"sync"
+)
+
+// commitHooks holds the work a transaction runs once it has committed
+type commitHooks struct {
+	mu    sync.Mutex
+	hooks []func()
+	ended bool
+}
+
+type commitHooksKey struct{}
+
+// withCommitHooks gives the context of a new transaction its hooks
+func withCommitHooks(ctx context.Context) (context.Context, *commitHooks) {
+	hooks := &commitHooks{}
+	return context.WithValue(ctx, commitHooksKey{}, hooks), hooks
+}
+
+// AfterCommit runs hook once the transaction of ctx has committed, or right away outside a transaction
+func AfterCommit(ctx context.Context, hook func()) {
+	if hooks, ok := ctx.Value(commitHooksKey{}).(*commitHooks); ok && hooks.add(hook) {
+		return
+	}
+	hook()
+}
+
+// add keeps hook for the commit, false when the transaction has already ended
+func (h *commitHooks) add(hook func()) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ended {
+		return false
+	}
+	h.hooks = append(h.hooks, hook)
+	return true
+}
+
+// InTransaction tells whether ctx belongs to a transaction that has not ended yet
+func InTransaction(ctx context.Context) bool {
+	hooks, ok := ctx.Value(commitHooksKey{}).(*commitHooks)
+	if !ok {
+		return false
+	}
+
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	return !hooks.ended
+}
+
+// run runs the hooks in the order they were added
+func (h *commitHooks) run() {
+	h.mu.Lock()
+	hooks := h.hooks
+	h.hooks = nil
+	h.ended = true
+	h.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+func (h *commitHooks) discard() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hooks = nil
+	h.ended = true
+}
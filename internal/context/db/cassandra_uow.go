@@ -0,0 +1,139 @@
+package context_db
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+)
+
+var ErrBatchDone = errors.New("cassandra batch already committed or rolled back")
+
+// CassandraTransaction runs the writes issued inside its context as one logged batch on Commit
+type CassandraTransaction struct {
+	ctx          context.Context
+	mu           sync.Mutex
+	session      *gocql.Session
+	batch        *gocql.Batch
+	compensation []gocql.BatchEntry // Undoes LWTs that could not wait for the batch
+	hooks        *commitHooks
+	done         bool
+}
+
+// Add queues a statement to run on Commit
+func (t *CassandraTransaction) Add(idempotent bool, stmt string, values ...interface{}) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.done {
+		return ErrBatchDone
+	}
+	t.batch.Entries = append(t.batch.Entries, gocql.BatchEntry{Stmt: stmt, Args: values, Idempotent: idempotent})
+	return nil
+}
+
+// Compensate registers a statement undoing an LWT that ran outside the batch
+func (t *CassandraTransaction) Compensate(stmt string, values ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.compensation = append(t.compensation, gocql.BatchEntry{Stmt: stmt, Args: values})
+}
+
+// Commit runs the batch, then what was deferred to the transaction with AfterCommit
+func (t *CassandraTransaction) Commit() error {
+	if err := t.commit(); err != nil {
+		t.hooks.discard()
+		return err
+	}
+	t.hooks.run()
+	return nil
+}
+
+func (t *CassandraTransaction) commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.done {
+		return ErrBatchDone
+	}
+	t.done = true
+
+	if t.batch.Size() == 0 {
+		return nil
+	}
+	if consistency, ok := GetConsistency(t.ctx); ok {
+		t.batch.Consistency(consistency)
+	}
+	if tracer, ok := GetTracer(t.ctx); ok {
+		t.batch.Trace(tracer)
+	}
+	if err := t.batch.ExecContext(t.ctx); err != nil {
+		return errors.Join(err, t.compensate())
+	}
+	return nil
+}
+
+// Rollback discards the queued statements, nothing of them was sent to Cassandra yet
+func (t *CassandraTransaction) Rollback() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.done {
+		return ErrBatchDone
+	}
+	t.done = true
+	t.batch.Entries = nil
+	t.hooks.discard()
+	return t.compensate()
+}
+
+// compensate runs each compensation statement once, a claim already gone is not an error
+func (t *CassandraTransaction) compensate() error {
+	var errs []error
+	for _, entry := range t.compensation {
+		query := t.session.Query(entry.Stmt, entry.Args...).Idempotent(false)
+		if consistency, ok := GetConsistency(t.ctx); ok {
+			query = query.Consistency(consistency)
+		}
+		if _, err := query.MapScanCASContext(t.ctx, map[string]interface{}{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	t.compensation = nil
+	return errors.Join(errs...)
+}
+
+type CassandraUnitOfWork struct {
+	session *gocql.Session
+}
+
+func NewCassandraUnitOfWork(session *gocql.Session) *CassandraUnitOfWork {
+	return &CassandraUnitOfWork{session: session}
+}
+
+type batchKey struct{}
+
+func (u *CassandraUnitOfWork) Begin(ctx context.Context) (usecase.Transaction, context.Context, error) {
+	tx := &CassandraTransaction{
+		ctx:     ctx,
+		session: u.session,
+		batch:   u.session.Batch(gocql.LoggedBatch),
+	}
+
+	// store batch in context
+	txCtx, hooks := withCommitHooks(context.WithValue(ctx, batchKey{}, tx))
+	tx.hooks = hooks
+
+	return tx, txCtx, nil
+}
+
+func GetBatch(ctx context.Context) *CassandraTransaction {
+	tx := ctx.Value(batchKey{})
+	if tx == nil {
+		return nil
+	}
+	return tx.(*CassandraTransaction)
+}
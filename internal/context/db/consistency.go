@@ -0,0 +1,22 @@
+package context_db
+
+import (
+	"context"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+)
+
+type consistencyKey struct{}
+
+// ConsistencyKey holds a per request gocql.Consistency, fiber handlers set it with Locals
+var ConsistencyKey = consistencyKey{}
+
+func WithConsistency(ctx context.Context, consistency gocql.Consistency) context.Context {
+	return context.WithValue(ctx, ConsistencyKey, consistency)
+}
+
+// GetConsistency returns the consistency requested for this context, false when the session default applies
+func GetConsistency(ctx context.Context) (gocql.Consistency, bool) {
+	consistency, ok := ctx.Value(ConsistencyKey).(gocql.Consistency)
+	return consistency, ok
+}
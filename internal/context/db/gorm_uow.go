@@ -8,14 +8,22 @@ import (
 )
 
 type GormTransaction struct {
-	tx *gorm.DB
+	tx    *gorm.DB
+	hooks *commitHooks
 }
 
+// Commit commits the transaction, then runs what was deferred to it with AfterCommit
 func (t *GormTransaction) Commit() error {
-	return t.tx.Commit().Error
+	if err := t.tx.Commit().Error; err != nil {
+		t.hooks.discard()
+		return err
+	}
+	t.hooks.run()
+	return nil
 }
 
 func (t *GormTransaction) Rollback() error {
+	t.hooks.discard()
 	return t.tx.Rollback().Error
 }
 
@@ -30,12 +38,12 @@ func NewGormUnitOfWork(db *gorm.DB) *GormUnitOfWork {
 type txKey struct{}
 
 func (u *GormUnitOfWork) Begin(ctx context.Context) (usecase.Transaction, context.Context, error) {
-	tx := u.db.Begin()
+	tx := u.db.WithContext(ctx).Begin()
 
 	// store tx in context
-	txCtx := context.WithValue(ctx, txKey{}, tx)
+	txCtx, hooks := withCommitHooks(context.WithValue(ctx, txKey{}, tx))
 
-	return &GormTransaction{tx}, txCtx, nil
+	return &GormTransaction{tx: tx, hooks: hooks}, txCtx, nil
 }
 
 func GetTx(ctx context.Context) *gorm.DB {
@@ -0,0 +1,156 @@
+package context_db
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+)
+
+var (
+	ErrMemoryTxDone   = errors.New("memory transaction already committed or rolled back")
+	ErrMemoryTxNested = errors.New("memory transactions cannot be nested")
+)
+
+// MemoryStore guards the state of the in-memory repositories sharing it, one transaction at a time
+type MemoryStore struct {
+	mu sync.RWMutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Read runs fn while the store cannot change, inside the transaction of ctx if there is one
+func (s *MemoryStore) Read(ctx context.Context, fn func()) {
+	if tx := s.transaction(ctx); tx != nil {
+		tx.mu.Lock()
+		defer tx.mu.Unlock()
+
+		if !tx.done {
+			fn()
+			return
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn()
+}
+
+// Write runs fn with the store to itself, fn registers how to revert its changes with undo
+func (s *MemoryStore) Write(ctx context.Context, fn func(undo func(func())) error) error {
+	if tx := s.transaction(ctx); tx != nil {
+		tx.mu.Lock()
+		defer tx.mu.Unlock()
+
+		if tx.done {
+			return ErrMemoryTxDone
+		}
+		return fn(func(step func()) {
+			tx.undo = append(tx.undo, step)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(func(func()) {})
+}
+
+// transaction returns the transaction of ctx when it belongs to this store
+func (s *MemoryStore) transaction(ctx context.Context) *MemoryTransaction {
+	tx := GetMemoryTx(ctx)
+	if tx == nil || tx.store != s {
+		return nil
+	}
+	return tx
+}
+
+// MemoryTransaction applies writes as they are made and keeps the steps undoing them until it ends
+type MemoryTransaction struct {
+	mu    sync.Mutex
+	store *MemoryStore
+	undo  []func()
+	hooks *commitHooks
+	done  bool
+}
+
+// Commit releases the store, then runs what was deferred to the transaction with AfterCommit
+func (t *MemoryTransaction) Commit() error {
+	if err := t.commit(); err != nil {
+		return err
+	}
+	t.hooks.run()
+	return nil
+}
+
+func (t *MemoryTransaction) commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.done {
+		return ErrMemoryTxDone
+	}
+	t.done = true
+	t.undo = nil
+	t.store.mu.Unlock()
+	return nil
+}
+
+// Rollback reverts the writes of the transaction newest first
+func (t *MemoryTransaction) Rollback() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.done {
+		return ErrMemoryTxDone
+	}
+	t.done = true
+	t.hooks.discard()
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		t.undo[i]()
+	}
+	t.undo = nil
+	t.store.mu.Unlock()
+	return nil
+}
+
+type MemoryUnitOfWork struct {
+	store *MemoryStore
+}
+
+func NewMemoryUnitOfWork(store *MemoryStore) *MemoryUnitOfWork {
+	return &MemoryUnitOfWork{store: store}
+}
+
+type memoryTxKey struct{}
+
+// Begin waits for the store, every transaction on it must end with Commit or Rollback
+func (u *MemoryUnitOfWork) Begin(ctx context.Context) (usecase.Transaction, context.Context, error) {
+	if outer := u.store.transaction(ctx); outer != nil {
+		outer.mu.Lock()
+		done := outer.done
+		outer.mu.Unlock()
+		if !done {
+			return nil, ctx, ErrMemoryTxNested
+		}
+	}
+
+	u.store.mu.Lock()
+	tx := &MemoryTransaction{store: u.store}
+
+	// store tx in context
+	txCtx, hooks := withCommitHooks(context.WithValue(ctx, memoryTxKey{}, tx))
+	tx.hooks = hooks
+
+	return tx, txCtx, nil
+}
+
+func GetMemoryTx(ctx context.Context) *MemoryTransaction {
+	tx := ctx.Value(memoryTxKey{})
+	if tx == nil {
+		return nil
+	}
+	return tx.(*MemoryTransaction)
+}
@@ -0,0 +1,22 @@
+package context_db
+
+import (
+	"context"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+)
+
+type tracerKey struct{}
+
+// TracerKey holds the gocql.Tracer of a request sampled for server side tracing
+var TracerKey = tracerKey{}
+
+func WithTracer(ctx context.Context, tracer gocql.Tracer) context.Context {
+	return context.WithValue(ctx, TracerKey, tracer)
+}
+
+// GetTracer returns the tracer of this context, false when its queries are not traced
+func GetTracer(ctx context.Context) (gocql.Tracer, bool) {
+	tracer, ok := ctx.Value(TracerKey).(gocql.Tracer)
+	return tracer, ok
+}
@@ -0,0 +1,17 @@
+package entity
+
+// BackfillCheckpoint is the last ID fully copied per table, a later run resumes after it
+type BackfillCheckpoint struct {
+	UsersAfter string `json:"users_after,omitempty"`
+	UsersDone  bool   `json:"users_done"`
+	BlogsAfter string `json:"blogs_after,omitempty"`
+	BlogsDone  bool   `json:"blogs_done"`
+}
+
+// BackfillReport summarizes one backfill run
+type BackfillReport struct {
+	DryRun     bool               `json:"dry_run"`
+	Users      int                `json:"users"`
+	Blogs      int                `json:"blogs"`
+	Checkpoint BackfillCheckpoint `json:"checkpoint"`
+}
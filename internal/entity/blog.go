@@ -7,9 +7,11 @@ import (
 )
 
 type Blog struct {
-	ID       uuid.UUID `json:"id,omitempty"` // Omit if zero UUID
-	Content  string    `json:"content"`
-	AuthorID uuid.UUID `json:"author_id,omitempty"` // Omit if zero UUID
-	Username string    `json:"username"`
-	Ts       time.Time `json:"ts,omitempty"` // Omit if nil
+	ID        uuid.UUID `json:"id,omitempty"` // Omit if zero UUID
+	Content   string    `json:"content"`
+	AuthorID  uuid.UUID `json:"author_id,omitempty"` // Omit if zero UUID
+	Username  string    `json:"username"`
+	Ts        time.Time `json:"ts,omitempty"`         // Omit if nil
+	ExpiresAt time.Time `json:"expires_at,omitempty"` // Zero when the blog never expires
+	Revision  int       `json:"revision"`             // 1 when created, counts up on every edit
 }
@@ -0,0 +1,10 @@
+package entity
+
+// BlogCountRepairReport summarizes one blog count repair run of a store
+type BlogCountRepairReport struct {
+	Store    string `json:"store"`
+	DryRun   bool   `json:"dry_run"`
+	Authors  int    `json:"authors"`
+	Repaired int    `json:"repaired"` // Authors whose count was off
+	Drift    int64  `json:"drift"`    // Sum of the absolute corrections
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BlogRevision is the content a blog had before an edit, revisions are never changed
+type BlogRevision struct {
+	BlogID    uuid.UUID `json:"blog_id"`
+	Revision  int       `json:"revision"`
+	Content   string    `json:"content"`
+	EditedAt  time.Time `json:"edited_at"`            // When this content was replaced
+	ExpiresAt time.Time `json:"expires_at,omitempty"` // The blog's expiry, the revision goes with it
+}
+
+// Diff line operations
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// BlogDiff is the line diff between two revisions of a blog
+type BlogDiff struct {
+	From  int        `json:"from"`
+	To    int        `json:"to"`
+	Lines []DiffLine `json:"lines"`
+}
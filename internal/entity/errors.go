@@ -0,0 +1,9 @@
+package entity
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("record not found")
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrConflict      = errors.New("record already exists")
+)
@@ -0,0 +1,27 @@
+package entity
+
+// TokenRange is a slice of the Murmur3 token ring, Start exclusive and End inclusive
+type TokenRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// ExportCheckpoint lists the token ranges already in the output file and the file size after them
+type ExportCheckpoint struct {
+	Table      string `json:"table"`
+	Format     string `json:"format"`
+	Splits     int    `json:"splits"`
+	Done       []int  `json:"done"` // Indexes of the exported ranges
+	OutputSize int64  `json:"output_size"`
+}
+
+// ExportReport summarizes one export run
+type ExportReport struct {
+	Table    string `json:"table"`
+	Format   string `json:"format"`
+	Ranges   int    `json:"ranges"`
+	Skipped  int    `json:"skipped"` // Ranges exported by an earlier run
+	Exported int    `json:"exported"`
+	Rows     int64  `json:"rows"`
+	Retries  int    `json:"retries"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Outbox event types
+const (
+	OutboxEventUserCreated = "user.created"
+	OutboxEventUserUpdated = "user.updated"
+)
+
+// Outbox event statuses, dead events exhausted their attempts and need an operator
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusDone    = "done"
+	OutboxStatusDead    = "dead"
+)
+
+// OutboxEvent is a change of the primary store to apply to the secondary store
+type OutboxEvent struct {
+	ID            uuid.UUID `json:"id,omitempty"`
+	AggregateID   uuid.UUID `json:"aggregate_id"`
+	EventType     string    `json:"event_type"`
+	Payload       []byte    `json:"payload"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	LastError     string    `json:"last_error,omitempty"`
+	NextAttemptAt time.Time `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time `json:"created_at,omitempty"`
+}
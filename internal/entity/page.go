@@ -0,0 +1,7 @@
+package entity
+
+// Page is a request for one page of a listing
+type Page struct {
+	Limit  int    `json:"limit"`
+	Cursor string `json:"cursor,omitempty"` // Opaque, empty for the first page
+}
@@ -0,0 +1,14 @@
+package entity
+
+// RebucketCheckpoint is the scan position in blogs_by_author after the last fully rewritten page
+type RebucketCheckpoint struct {
+	Cursor string `json:"cursor,omitempty"`
+	Done   bool   `json:"done"`
+}
+
+// RebucketReport summarizes one rebucket run
+type RebucketReport struct {
+	DryRun     bool               `json:"dry_run"`
+	Blogs      int                `json:"blogs"`
+	Checkpoint RebucketCheckpoint `json:"checkpoint"`
+}
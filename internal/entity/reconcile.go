@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+// Repair directions of the reconciler, the first store is the source of truth
+const (
+	RepairNone                = "none"
+	RepairPostgresToCassandra = "postgres_to_cassandra"
+	RepairCassandraToPostgres = "cassandra_to_postgres"
+)
+
+// Reconcile problems
+const (
+	DriftMissingInCassandra = "missing_in_cassandra"
+	DriftMissingInPostgres  = "missing_in_postgres"
+	DriftMismatch           = "mismatch"
+)
+
+// ReconcileDiff is one row that differs between Postgres and Cassandra
+type ReconcileDiff struct {
+	Kind     string   `json:"kind"` // user or blog
+	ID       string   `json:"id"`
+	AuthorID string   `json:"author_id,omitempty"`
+	Problem  string   `json:"problem"`
+	Fields   []string `json:"fields,omitempty"` // Mismatched fields
+	Repaired bool     `json:"repaired"`
+	Error    string   `json:"error,omitempty"` // Why the repair failed
+}
+
+// ReconcileCounts counts the drift of one kind of row
+type ReconcileCounts struct {
+	Checked            int `json:"checked"`
+	MissingInCassandra int `json:"missing_in_cassandra"`
+	MissingInPostgres  int `json:"missing_in_postgres"`
+	Mismatched         int `json:"mismatched"`
+	Repaired           int `json:"repaired"`
+}
+
+// ReconcileReport is the machine readable result of a reconcile run
+type ReconcileReport struct {
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Repair     string          `json:"repair"`
+	InSync     bool            `json:"in_sync"`
+	Users      ReconcileCounts `json:"users"`
+	Blogs      ReconcileCounts `json:"blogs"`
+	Diffs      []ReconcileDiff `json:"diffs"`
+	Truncated  bool            `json:"truncated"` // Diffs was capped, the counts are still complete
+}
@@ -0,0 +1,15 @@
+package entity
+
+// RetagCheckpoint is the scan position in the blog store after the last fully tagged page
+type RetagCheckpoint struct {
+	Cursor string `json:"cursor,omitempty"`
+	Done   bool   `json:"done"`
+}
+
+// RetagReport summarizes one retag run
+type RetagReport struct {
+	DryRun     bool            `json:"dry_run"`
+	Blogs      int             `json:"blogs"`  // Blogs read
+	Tagged     int             `json:"tagged"` // Blogs with tags filed under them
+	Checkpoint RetagCheckpoint `json:"checkpoint"`
+}
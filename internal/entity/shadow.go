@@ -0,0 +1,8 @@
+package entity
+
+// ShadowStats counts the shadow reads of one resource
+type ShadowStats struct {
+	Compared   int64 `json:"compared"`
+	Mismatched int64 `json:"mismatched"`
+	Failed     int64 `json:"failed"` // The secondary read itself failed
+}
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MaxTagLength = 64 // Longer #words are not tags
+	MaxBlogTags  = 20 // Tags after the first MaxBlogTags of a blog are ignored
+)
+
+// TagCount is how many blogs used a tag
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Blogs int64  `json:"blogs"`
+}
+
+// Tags parses the #tags of the content, lowercased without the #, in order of first use
+func (b Blog) Tags() []string {
+	tags := []string{}
+	seen := map[string]bool{}
+
+	content := b.Content
+	previous := ' '
+	for i, r := range content {
+		if r != '#' || isTagRune(previous) {
+			previous = r
+			continue
+		}
+		previous = r
+
+		end := i + 1
+		for end < len(content) {
+			next, size := utf8.DecodeRuneInString(content[end:])
+			if !isTagRune(next) {
+				break
+			}
+			end += size
+		}
+
+		if tag, ok := NormalizeTag(content[i+1 : end]); ok && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+			if len(tags) == MaxBlogTags {
+				break
+			}
+		}
+	}
+
+	return tags
+}
+
+// NormalizeTag lowercases a tag given without its #, ok is false when it is not a valid tag
+func NormalizeTag(tag string) (string, bool) {
+	if tag == "" || utf8.RuneCountInString(tag) > MaxTagLength {
+		return "", false
+	}
+
+	letter := false
+	for _, r := range tag {
+		if !isTagRune(r) {
+			return "", false
+		}
+		letter = letter || unicode.IsLetter(r)
+	}
+	if !letter {
+		return "", false
+	}
+
+	return strings.ToLower(tag), true
+}
+
+func isTagRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeedPosition is where a feed page ended, the zero value starts from the newest blog
+type FeedPosition struct {
+	Ts time.Time `json:"ts"`
+	ID uuid.UUID `json:"id"`
+}
+
+func (p FeedPosition) IsZero() bool {
+	return p.Ts.IsZero() && p.ID == uuid.Nil
+}
+
+// FeedPositionOf is where a blog sits in a feed, ordered by the time to the second, then by ID
+func FeedPositionOf(blog Blog) FeedPosition {
+	return FeedPosition{Ts: time.Unix(blog.Ts.Unix(), 0), ID: blog.ID}
+}
+
+// Before tells whether p comes before q in a feed, i.e. whether it is newer
+func (p FeedPosition) Before(q FeedPosition) bool {
+	if p.Ts.Unix() != q.Ts.Unix() {
+		return p.Ts.Unix() > q.Ts.Unix()
+	}
+	return bytes.Compare(p.ID[:], q.ID[:]) > 0
+}
+
+// Encode makes the opaque cursor of a page ending at the position
+func (p FeedPosition) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", p.Ts.Unix(), p.ID)))
+}
+
+// DecodeFeedPosition reads a cursor made by Encode, an empty cursor is the zero position
+func DecodeFeedPosition(cursor string) (FeedPosition, error) {
+	if cursor == "" {
+		return FeedPosition{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return FeedPosition{}, ErrInvalidCursor
+	}
+	tsStr, idStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return FeedPosition{}, ErrInvalidCursor
+	}
+	ts, err := strconv.ParseInt(tsStr, 10, 64)
+	if err != nil {
+		return FeedPosition{}, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return FeedPosition{}, ErrInvalidCursor
+	}
+
+	return FeedPosition{Ts: time.Unix(ts, 0), ID: id}, nil
+}
@@ -3,6 +3,7 @@ package rest
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/jinzhu/copier"
@@ -13,7 +14,13 @@ import (
 
 type IBlogUseCase interface {
 	CreateBlog(ctx context.Context, request entity.Blog) (entity.Blog, error)
-	GetBlogs(ctx context.Context) ([]entity.Blog, error)
+	GetBlogs(ctx context.Context, page entity.Page) ([]entity.Blog, string, error)
+	GetBlog(ctx context.Context, blogID string) (entity.Blog, error)
+	UpdateBlog(ctx context.Context, blogID string, request entity.Blog) (entity.Blog, error)
+	DeleteBlog(ctx context.Context, blogID string) error
+	GetRevisions(ctx context.Context, blogID string) ([]entity.BlogRevision, error)
+	DiffRevisions(ctx context.Context, blogID string, from int, to int) (entity.BlogDiff, error)
+	SearchBlogs(ctx context.Context, query string, page entity.Page) ([]entity.Blog, string, error)
 }
 
 type BlogHandler struct {
@@ -42,6 +49,10 @@ func (h *BlogHandler) CreateBlog(c *fiber.Ctx) error {
 		return err
 	}
 
+	if request.ExpiresIn != nil {
+		blogInput.ExpiresAt = time.Now().Add(time.Duration(*request.ExpiresIn) * time.Second)
+	}
+
 	fmt.Println("blogInput", blogInput)
 
 	blog, err := h.UseCase.CreateBlog(c.Context(), blogInput)
@@ -54,30 +65,152 @@ func (h *BlogHandler) CreateBlog(c *fiber.Ctx) error {
 	})
 }
 
-func (h *BlogHandler) Blogs(c *fiber.Ctx) error {
-	blogs, err := h.UseCase.GetBlogs(c.Context())
+func (h *BlogHandler) Blogs(c *fiber.Ctx, params model.BlogsParams) error {
+	page := entity.Page{}
+	if params.Limit != nil {
+		page.Limit = *params.Limit
+	}
+	if params.Cursor != nil {
+		page.Cursor = *params.Cursor
+	}
+
+	blogs, nextCursor, err := h.UseCase.GetBlogs(c.Context(), page)
 	if err != nil {
 		return err
 	}
 
-	var blogsResponse []model.Blog
+	blogsResponse := make([]model.Blog, 0, len(blogs))
 	for _, blog := range blogs {
 		blogsResponse = append(blogsResponse, convertToBlogResponse(blog))
 	}
 
-	return c.JSON(fiber.Map{
-		"data": blogsResponse,
-	})
+	response := model.BlogPage{
+		Data: blogsResponse,
+	}
+	if nextCursor != "" {
+		response.NextCursor = &nextCursor
+	}
+
+	return c.JSON(response)
+}
+
+func (h *BlogHandler) SearchBlogs(c *fiber.Ctx, params model.SearchBlogsParams) error {
+	page := entity.Page{}
+	if params.Limit != nil {
+		page.Limit = *params.Limit
+	}
+	if params.Cursor != nil {
+		page.Cursor = *params.Cursor
+	}
+
+	blogs, nextCursor, err := h.UseCase.SearchBlogs(c.Context(), params.Q, page)
+	if err != nil {
+		return err
+	}
+
+	blogsResponse := make([]model.Blog, 0, len(blogs))
+	for _, blog := range blogs {
+		blogsResponse = append(blogsResponse, convertToBlogResponse(blog))
+	}
+
+	response := model.BlogPage{
+		Data: blogsResponse,
+	}
+	if nextCursor != "" {
+		response.NextCursor = &nextCursor
+	}
+
+	return c.JSON(response)
+}
+
+func (h *BlogHandler) GetBlog(c *fiber.Ctx, id string) error {
+	blog, err := h.UseCase.GetBlog(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(convertToBlogResponse(blog))
+}
+
+func (h *BlogHandler) UpdateBlog(c *fiber.Ctx, id string) error {
+	request := model.UpdateBlogRequest{}
+	if err := c.BodyParser(&request); err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	blog, err := h.UseCase.UpdateBlog(c.Context(), id, entity.Blog{Content: request.Content})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(convertToBlogResponse(blog))
+}
+
+func (h *BlogHandler) DeleteBlog(c *fiber.Ctx, id string) error {
+	if err := h.UseCase.DeleteBlog(c.Context(), id); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *BlogHandler) GetBlogRevisions(c *fiber.Ctx, id string) error {
+	revisions, err := h.UseCase.GetRevisions(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	response := model.BlogRevisionList{
+		Data: make([]model.BlogRevision, 0, len(revisions)),
+	}
+	for _, revision := range revisions {
+		response.Data = append(response.Data, model.BlogRevision{
+			Revision: revision.Revision,
+			Content:  revision.Content,
+			EditedAt: revision.EditedAt.Unix(),
+		})
+	}
+
+	return c.JSON(response)
+}
+
+func (h *BlogHandler) DiffBlogRevisions(c *fiber.Ctx, id string, params model.DiffBlogRevisionsParams) error {
+	diff, err := h.UseCase.DiffRevisions(c.Context(), id, params.From, params.To)
+	if err != nil {
+		return err
+	}
+
+	response := model.BlogDiff{
+		From:  diff.From,
+		To:    diff.To,
+		Lines: make([]model.BlogDiffLine, 0, len(diff.Lines)),
+	}
+	for _, line := range diff.Lines {
+		response.Lines = append(response.Lines, model.BlogDiffLine{
+			Op:   model.BlogDiffLineOp(line.Op),
+			Text: line.Text,
+		})
+	}
+
+	return c.JSON(response)
 }
 
 func convertToBlogResponse(blog entity.Blog) model.Blog {
 	authorId := blog.AuthorID.String()
 
-	return model.Blog{
+	response := model.Blog{
 		Id:       blog.ID.String(),
 		Content:  blog.Content,
 		AuthorId: &authorId,
 		Username: blog.Username,
 		Ts:       blog.Ts.Unix(),
+		Revision: blog.Revision,
+		Tags:     blog.Tags(),
 	}
+	if !blog.ExpiresAt.IsZero() {
+		expiresAt := blog.ExpiresAt.Unix()
+		response.ExpiresAt = &expiresAt
+	}
+
+	return response
 }
@@ -0,0 +1,14 @@
+package context
+
+import (
+	"context"
+)
+
+// RequestIDKey holds the request ID, the fiber requestid middleware stores it with Locals
+const RequestIDKey = "requestid"
+
+// GetRequestIDFromContext returns the request ID, empty outside of a request
+func GetRequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(RequestIDKey).(string)
+	return requestID
+}
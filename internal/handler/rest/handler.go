@@ -5,9 +5,11 @@ type APIHandler struct {
 	*GenericHandler
 	*UserHandler
 	*BlogHandler
+	*TimelineHandler
+	*TagHandler
 }
 
 // constructor
-func NewAPIHandler(generic *GenericHandler, user *UserHandler, blog *BlogHandler) *APIHandler {
-	return &APIHandler{generic, user, blog}
+func NewAPIHandler(generic *GenericHandler, user *UserHandler, blog *BlogHandler, timeline *TimelineHandler, tag *TagHandler) *APIHandler {
+	return &APIHandler{generic, user, blog, timeline, tag}
 }
@@ -0,0 +1,30 @@
+package rest
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type IMetricsRecorder interface {
+	WritePrometheus(w io.Writer) error
+}
+
+type MetricsHandler struct {
+	Log      *logrus.Logger
+	Recorder IMetricsRecorder
+}
+
+func NewMetricsHandler(recorder IMetricsRecorder, logger *logrus.Logger) *MetricsHandler {
+	return &MetricsHandler{
+		Log:      logger,
+		Recorder: recorder,
+	}
+}
+
+// Metrics serves the statement latency histograms in the Prometheus text format
+func (h *MetricsHandler) Metrics(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+	return h.Recorder.WritePrometheus(c)
+}
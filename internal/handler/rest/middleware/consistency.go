@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"strings"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/gofiber/fiber/v2"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ConsistencyHeader     = "X-Consistency-Level"
+	ConsistencyQueryParam = "consistency"
+)
+
+// NewConsistency lets a request pick an allowed Cassandra consistency with the X-Consistency-Level header
+func NewConsistency(allowed []gocql.Consistency, defaultConsistency gocql.Consistency, logger *logrus.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		requested := ctx.Get(ConsistencyHeader)
+		if requested == "" {
+			requested = ctx.Query(ConsistencyQueryParam)
+		}
+
+		if requested == "" {
+			ctx.Set(ConsistencyHeader, defaultConsistency.String())
+			return ctx.Next()
+		}
+
+		consistency, err := gocql.ParseConsistencyWrapper(strings.ToUpper(requested))
+		if err != nil || !allowedConsistency(allowed, consistency) {
+			logger.Warnf("Consistency level %s is not allowed", requested)
+			return fiber.NewError(fiber.StatusBadRequest, "consistency level not allowed: "+requested)
+		}
+
+		ctx.Locals(context_db.ConsistencyKey, consistency)
+		ctx.Set(ConsistencyHeader, consistency.String())
+		return ctx.Next()
+	}
+}
+
+func allowedConsistency(allowed []gocql.Consistency, consistency gocql.Consistency) bool {
+	for _, a := range allowed {
+		if a == consistency {
+			return true
+		}
+	}
+	return false
+}
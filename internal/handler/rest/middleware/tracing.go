@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"math/rand"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/gofiber/fiber/v2"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	authContext "github.com/rifkiadrn/cassandra-explore/internal/handler/rest/context"
+)
+
+// NewQueryTracing enables Cassandra server side tracing for samplePercent of the requests
+func NewQueryTracing(samplePercent float64, newTracer func(requestID string) gocql.Tracer) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if rand.Float64()*100 < samplePercent {
+			requestID, _ := ctx.Locals(authContext.RequestIDKey).(string)
+			ctx.Locals(context_db.TracerKey, newTracer(requestID))
+		}
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type IOutboxUseCase interface {
+	Stats(ctx context.Context) (map[string]int64, error)
+}
+
+type OutboxHandler struct {
+	Log     *logrus.Logger
+	UseCase IOutboxUseCase
+}
+
+func NewOutboxHandler(useCase IOutboxUseCase, logger *logrus.Logger) *OutboxHandler {
+	return &OutboxHandler{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Stats reports outbox events per status
+func (h *OutboxHandler) Stats(c *fiber.Ctx) error {
+	stats, err := h.UseCase.Stats(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": stats,
+	})
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
+)
+
+type IReconcileUseCase interface {
+	Run(ctx context.Context, repair bool) (entity.ReconcileReport, error)
+}
+
+type ReconcileHandler struct {
+	Log     *logrus.Logger
+	UseCase IReconcileUseCase
+}
+
+func NewReconcileHandler(useCase IReconcileUseCase, logger *logrus.Logger) *ReconcileHandler {
+	return &ReconcileHandler{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Reconcile diffs Postgres and Cassandra, repairs are left to cmd/reconcile -repair
+func (h *ReconcileHandler) Reconcile(c *fiber.Ctx) error {
+	report, err := h.UseCase.Run(c.Context(), false)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": report,
+	})
+}
@@ -8,19 +8,30 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	fiberMiddleware "github.com/oapi-codegen/fiber-middleware"
 	"github.com/rifkiadrn/cassandra-explore/internal/handler/rest"
+	authContext "github.com/rifkiadrn/cassandra-explore/internal/handler/rest/context"
 	"github.com/sirupsen/logrus"
 )
 
 type RouterConfig struct {
-	App            *fiber.App
-	APIHandler     rest.APIHandler
-	AuthMiddleware fiber.Handler
-	Log            *logrus.Logger
+	App                   *fiber.App
+	APIHandler            rest.APIHandler
+	AuthMiddleware        fiber.Handler
+	ConsistencyMiddleware fiber.Handler // nil when consistency overrides are off
+	TracingMiddleware     fiber.Handler // nil when query tracing is off
+	MetricsHandler        *rest.MetricsHandler
+	OutboxHandler         *rest.OutboxHandler    // nil when nothing is replicated
+	ReconcileHandler      *rest.ReconcileHandler // nil when storage.mode is memory
+	ShadowHandler         *rest.ShadowHandler    // nil when shadow reads are off
+	Log                   *logrus.Logger
 }
 
 func (r *RouterConfig) Setup() {
+	// X-Request-ID, generated unless the caller sent one, ties query logs and traces to the request
+	r.App.Use(requestid.New(requestid.Config{ContextKey: authContext.RequestIDKey}))
+
 	r.App.Get("/ping", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
 			"message": "pong",
@@ -39,11 +50,23 @@ func (r *RouterConfig) Setup() {
 		})
 	})
 
-	// API exposes: /interal/metrics
-	internal.Get("/metrics", func(c *fiber.Ctx) error {
-		// Expose Prometheus or other metrics
-		return c.SendString("prometheus metrics here")
-	})
+	// API exposes: /internal/metrics
+	internal.Get("/metrics", r.MetricsHandler.Metrics)
+
+	// API exposes: /internal/outbox
+	if r.OutboxHandler != nil {
+		internal.Get("/outbox", r.OutboxHandler.Stats)
+	}
+
+	// API exposes: /internal/reconcile, diff only, repairs run from cmd/reconcile
+	if r.ReconcileHandler != nil {
+		internal.Post("/reconcile", r.ReconcileHandler.Reconcile)
+	}
+
+	// API exposes: /internal/shadow
+	if r.ShadowHandler != nil {
+		internal.Get("/shadow", r.ShadowHandler.Stats)
+	}
 
 	swagger, err := rest.GetSwagger()
 	if err != nil {
@@ -55,6 +78,16 @@ func (r *RouterConfig) Setup() {
 
 	api := r.App.Group("/api/v1")
 
+	// per request Cassandra consistency, X-Consistency-Level or ?consistency=
+	if r.ConsistencyMiddleware != nil {
+		api.Use(r.ConsistencyMiddleware)
+	}
+
+	// Cassandra server side tracing of a sample of the requests
+	if r.TracingMiddleware != nil {
+		api.Use(r.TracingMiddleware)
+	}
+
 	api.Use(func(c *fiber.Ctx) error {
 		// Run OAPI validator manually with injected AuthenticationFunc
 		validator := fiberMiddleware.OapiRequestValidatorWithOptions(swagger, &fiberMiddleware.Options{
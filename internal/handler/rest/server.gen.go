@@ -14,6 +14,7 @@ import (
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gofiber/fiber/v2"
+	"github.com/oapi-codegen/runtime"
 
 	model "github.com/rifkiadrn/cassandra-explore/internal/model"
 )
@@ -25,13 +26,49 @@ type ServerInterface interface {
 	LoginUser(c *fiber.Ctx) error
 	// Get all blogs
 	// (GET /blogs)
-	Blogs(c *fiber.Ctx) error
+	Blogs(c *fiber.Ctx, params model.BlogsParams) error
 	// Create a blog
 	// (POST /blogs)
 	CreateBlog(c *fiber.Ctx) error
+	// Search blogs
+	// (GET /blogs/search)
+	SearchBlogs(c *fiber.Ctx, params model.SearchBlogsParams) error
+	// Delete a blog
+	// (DELETE /blogs/{id})
+	DeleteBlog(c *fiber.Ctx, id string) error
+	// Get a blog
+	// (GET /blogs/{id})
+	GetBlog(c *fiber.Ctx, id string) error
+	// Update a blog
+	// (PATCH /blogs/{id})
+	UpdateBlog(c *fiber.Ctx, id string) error
+	// List the revisions of a blog
+	// (GET /blogs/{id}/revisions)
+	GetBlogRevisions(c *fiber.Ctx, id string) error
+	// Diff two revisions of a blog
+	// (GET /blogs/{id}/revisions/diff)
+	DiffBlogRevisions(c *fiber.Ctx, id string, params model.DiffBlogRevisionsParams) error
+	// List the most used tags
+	// (GET /tags/top)
+	GetTopTags(c *fiber.Ctx, params model.GetTopTagsParams) error
+	// List the blogs of a tag
+	// (GET /tags/{tag}/blogs)
+	GetTagBlogs(c *fiber.Ctx, tag string, params model.GetTagBlogsParams) error
+	// Get the home timeline
+	// (GET /timeline)
+	Timeline(c *fiber.Ctx, params model.TimelineParams) error
 	// Register a new user
 	// (POST /users)
 	RegisterUser(c *fiber.Ctx) error
+	// Get a user profile
+	// (GET /users/{id})
+	GetUser(c *fiber.Ctx, id string) error
+	// Unfollow a user
+	// (DELETE /users/{id}/follow)
+	UnfollowUser(c *fiber.Ctx, id string) error
+	// Follow a user
+	// (POST /users/{id}/follow)
+	FollowUser(c *fiber.Ctx, id string) error
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -50,11 +87,36 @@ func (siw *ServerInterfaceWrapper) LoginUser(c *fiber.Ctx) error {
 // Blogs operation middleware
 func (siw *ServerInterfaceWrapper) Blogs(c *fiber.Ctx) error {
 
+	var err error
+
 	c.Context().SetUserValue(model.BearerAuthScopes, []string{})
 
 	c.Context().SetUserValue(model.ApiKeyAuthScopes, []string{})
 
-	return siw.Handler.Blogs(c)
+	// Parameter object where we will unmarshal all parameters from the context
+	var params model.BlogsParams
+
+	var query url.Values
+	query, err = url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for query string: %w", err).Error())
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", query, &params.Limit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter limit: %w", err).Error())
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", query, &params.Cursor)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter cursor: %w", err).Error())
+	}
+
+	return siw.Handler.Blogs(c, params)
 }
 
 // CreateBlog operation middleware
@@ -67,12 +129,374 @@ func (siw *ServerInterfaceWrapper) CreateBlog(c *fiber.Ctx) error {
 	return siw.Handler.CreateBlog(c)
 }
 
+// SearchBlogs operation middleware
+func (siw *ServerInterfaceWrapper) SearchBlogs(c *fiber.Ctx) error {
+
+	var err error
+
+	c.Context().SetUserValue(model.BearerAuthScopes, []string{})
+
+	c.Context().SetUserValue(model.ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params model.SearchBlogsParams
+
+	var query url.Values
+	query, err = url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for query string: %w", err).Error())
+	}
+
+	// ------------- Required query parameter "q" -------------
+
+	if paramValue := c.Query("q"); paramValue != "" {
+
+	} else {
+		err = fmt.Errorf("Query argument q is required, but not found")
+		c.Status(fiber.StatusBadRequest).JSON(err)
+		return err
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", query, &params.Q)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter q: %w", err).Error())
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", query, &params.Limit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter limit: %w", err).Error())
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", query, &params.Cursor)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter cursor: %w", err).Error())
+	}
+
+	return siw.Handler.SearchBlogs(c, params)
+}
+
+// DeleteBlog operation middleware
+func (siw *ServerInterfaceWrapper) DeleteBlog(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Params("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter id: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(model.BearerAuthScopes, []string{})
+
+	c.Context().SetUserValue(model.ApiKeyAuthScopes, []string{})
+
+	return siw.Handler.DeleteBlog(c, id)
+}
+
+// GetBlog operation middleware
+func (siw *ServerInterfaceWrapper) GetBlog(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Params("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter id: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(model.BearerAuthScopes, []string{})
+
+	c.Context().SetUserValue(model.ApiKeyAuthScopes, []string{})
+
+	return siw.Handler.GetBlog(c, id)
+}
+
+// UpdateBlog operation middleware
+func (siw *ServerInterfaceWrapper) UpdateBlog(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Params("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter id: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(model.BearerAuthScopes, []string{})
+
+	c.Context().SetUserValue(model.ApiKeyAuthScopes, []string{})
+
+	return siw.Handler.UpdateBlog(c, id)
+}
+
+// GetBlogRevisions operation middleware
+func (siw *ServerInterfaceWrapper) GetBlogRevisions(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Params("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter id: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(model.BearerAuthScopes, []string{})
+
+	c.Context().SetUserValue(model.ApiKeyAuthScopes, []string{})
+
+	return siw.Handler.GetBlogRevisions(c, id)
+}
+
+// DiffBlogRevisions operation middleware
+func (siw *ServerInterfaceWrapper) DiffBlogRevisions(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Params("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter id: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(model.BearerAuthScopes, []string{})
+
+	c.Context().SetUserValue(model.ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params model.DiffBlogRevisionsParams
+
+	var query url.Values
+	query, err = url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for query string: %w", err).Error())
+	}
+
+	// ------------- Required query parameter "from" -------------
+
+	if paramValue := c.Query("from"); paramValue != "" {
+
+	} else {
+		err = fmt.Errorf("Query argument from is required, but not found")
+		c.Status(fiber.StatusBadRequest).JSON(err)
+		return err
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "from", query, &params.From)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter from: %w", err).Error())
+	}
+
+	// ------------- Required query parameter "to" -------------
+
+	if paramValue := c.Query("to"); paramValue != "" {
+
+	} else {
+		err = fmt.Errorf("Query argument to is required, but not found")
+		c.Status(fiber.StatusBadRequest).JSON(err)
+		return err
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "to", query, &params.To)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter to: %w", err).Error())
+	}
+
+	return siw.Handler.DiffBlogRevisions(c, id, params)
+}
+
+// GetTopTags operation middleware
+func (siw *ServerInterfaceWrapper) GetTopTags(c *fiber.Ctx) error {
+
+	var err error
+
+	c.Context().SetUserValue(model.BearerAuthScopes, []string{})
+
+	c.Context().SetUserValue(model.ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params model.GetTopTagsParams
+
+	var query url.Values
+	query, err = url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for query string: %w", err).Error())
+	}
+
+	// ------------- Optional query parameter "hours" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "hours", query, &params.Hours)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter hours: %w", err).Error())
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", query, &params.Limit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter limit: %w", err).Error())
+	}
+
+	return siw.Handler.GetTopTags(c, params)
+}
+
+// GetTagBlogs operation middleware
+func (siw *ServerInterfaceWrapper) GetTagBlogs(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "tag" -------------
+	var tag string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tag", c.Params("tag"), &tag, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter tag: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(model.BearerAuthScopes, []string{})
+
+	c.Context().SetUserValue(model.ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params model.GetTagBlogsParams
+
+	var query url.Values
+	query, err = url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for query string: %w", err).Error())
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", query, &params.Limit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter limit: %w", err).Error())
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", query, &params.Cursor)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter cursor: %w", err).Error())
+	}
+
+	return siw.Handler.GetTagBlogs(c, tag, params)
+}
+
+// Timeline operation middleware
+func (siw *ServerInterfaceWrapper) Timeline(c *fiber.Ctx) error {
+
+	var err error
+
+	c.Context().SetUserValue(model.BearerAuthScopes, []string{})
+
+	c.Context().SetUserValue(model.ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params model.TimelineParams
+
+	var query url.Values
+	query, err = url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for query string: %w", err).Error())
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", query, &params.Limit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter limit: %w", err).Error())
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", query, &params.Cursor)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter cursor: %w", err).Error())
+	}
+
+	return siw.Handler.Timeline(c, params)
+}
+
 // RegisterUser operation middleware
 func (siw *ServerInterfaceWrapper) RegisterUser(c *fiber.Ctx) error {
 
 	return siw.Handler.RegisterUser(c)
 }
 
+// GetUser operation middleware
+func (siw *ServerInterfaceWrapper) GetUser(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Params("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter id: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(model.BearerAuthScopes, []string{})
+
+	c.Context().SetUserValue(model.ApiKeyAuthScopes, []string{})
+
+	return siw.Handler.GetUser(c, id)
+}
+
+// UnfollowUser operation middleware
+func (siw *ServerInterfaceWrapper) UnfollowUser(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Params("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter id: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(model.BearerAuthScopes, []string{})
+
+	c.Context().SetUserValue(model.ApiKeyAuthScopes, []string{})
+
+	return siw.Handler.UnfollowUser(c, id)
+}
+
+// FollowUser operation middleware
+func (siw *ServerInterfaceWrapper) FollowUser(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Params("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter id: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(model.BearerAuthScopes, []string{})
+
+	c.Context().SetUserValue(model.ApiKeyAuthScopes, []string{})
+
+	return siw.Handler.FollowUser(c, id)
+}
+
 // FiberServerOptions provides options for the Fiber server.
 type FiberServerOptions struct {
 	BaseURL     string
@@ -100,29 +524,81 @@ func RegisterHandlersWithOptions(router fiber.Router, si ServerInterface, option
 
 	router.Post(options.BaseURL+"/blogs", wrapper.CreateBlog)
 
+	router.Get(options.BaseURL+"/blogs/search", wrapper.SearchBlogs)
+
+	router.Delete(options.BaseURL+"/blogs/:id", wrapper.DeleteBlog)
+
+	router.Get(options.BaseURL+"/blogs/:id", wrapper.GetBlog)
+
+	router.Patch(options.BaseURL+"/blogs/:id", wrapper.UpdateBlog)
+
+	router.Get(options.BaseURL+"/blogs/:id/revisions", wrapper.GetBlogRevisions)
+
+	router.Get(options.BaseURL+"/blogs/:id/revisions/diff", wrapper.DiffBlogRevisions)
+
+	router.Get(options.BaseURL+"/tags/top", wrapper.GetTopTags)
+
+	router.Get(options.BaseURL+"/tags/:tag/blogs", wrapper.GetTagBlogs)
+
+	router.Get(options.BaseURL+"/timeline", wrapper.Timeline)
+
 	router.Post(options.BaseURL+"/users", wrapper.RegisterUser)
 
+	router.Get(options.BaseURL+"/users/:id", wrapper.GetUser)
+
+	router.Delete(options.BaseURL+"/users/:id/follow", wrapper.UnfollowUser)
+
+	router.Post(options.BaseURL+"/users/:id/follow", wrapper.FollowUser)
+
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8RWXW/cNhD8KwTbR+U+4iRA9dRzmxZODDRwY6SFcQhoae/ERCKV5crOwdB/L5b6Op50",
-	"tY06zZPP5JI7M8ud1Z1MbFFaA4acjO+kSzIolP95mtst/y3RloCkwa+qijKLZyn/pl0JMpaOUJutrCOZ",
-	"WENgaHJPTx9p0m4sFopkLLWhVy9k1MVpQ7AF5MDKARpVwMQtdSQRvlQaIZXxFWcaoEQt4o9+tb/EJ173",
-	"eez1J0iI0/yCoAiY+wV8qcDRWILjLA+AdIFTec7tVpsLcKU1DsY5yH4Gwz9ScAnqkrQ1MpZvPrwXfkts",
-	"LApmBoZ0ovx2NFaX+fItPyJsZCx/mA/Vnrelnl9yzCH0Jn97wVH8l+31IfZSOXdr0de7UF/PwWwpk/Fy",
-	"sYhkoU33/6sjeLsi7x19GZ48ie4Rfq/MPZgpEhew1Y4Ap3lMADnkcDLB4fvTb6k/VIVp9olvhPSjogc2",
-	"6JEGP9KzoU5jW+je/1ijMn0crkcax7+IF/VtsSdOgGgsbx1JB0mFmnZ/csM14q5K/RZ2q4rLeSc1t3YG",
-	"KgXsAMTyr2erd2fP3r7+eyCl/CnmdAoKAbvz1/6/3zop3nx4L6PGyflUszvckhGVsmZg2mxsZ2gq8XpC",
-	"oXQuY4l681mrFM3y+c9bXpslthjAXfC2WKWolWE8oUv9UYIRq3dnwpWQ6E3rT+I200kmmtBrcIIy8FFs",
-	"Zb8qUuL1OQMnTTnn6JY4RkbyBtA11y9ni9mCs9oSjCq1jOXJbDlb+FJR5gWeszfOczYp/7RtY+QhztXg",
-	"nyC44EKZVCBQhUb0VjuTPhN6Ejz49ryveT/g6NSmu4PZoMoyb6nPPzlrhvF6nyMP99fhEyWswC80Y8Mz",
-	"fb5YPG3ifij55KFiPkC4KknAuU2Vz7gMLxoEYeSZuVG5ToU2ZUVN1PJ4VIKQciVU7jj25fSNxC2ZCwd4",
-	"AygA0WLQYDK+WkfSVUWhcNejrbr5Nr/O7dZrtgUvVFjVU7/7H9XVBIW7T2b/ZVUPbY2odpNqa0fCbkSD",
-	"2zPtuf0OJFSed3tR/8RDUsPnzDd6q+PvpQe92eWTAWjEHIvH66L16QPtGsxCefGap8GPxB13iv6EgdvW",
-	"KZLEVobG5hB8U3wbzYMU/7PcQ85QIV7v5N4ziHz3KIv4aRx12Q5ioXIEle4EfNWOntImOjn3ytsw3Dtw",
-	"F4zcq3UdhUP8al3znT6l8/EV8hydy3pd/xMAAP//kgUyH2UNAAA=",
+	"H4sIAAAAAAAC/+xabW/bOBL+KwP1Pqqxnb4szp8u21yL7HZvizRB71AEAS2NLW4lUiGpOEaQ/34Yknqz",
+	"qdjuxt0u0C9tLJGc4cwzD2eGuo8SWZRSoDA6mt5HOsmwYPbPn3O5oP9LJUtUhqN9yiqTSXWW0t9mVWI0",
+	"jbRRXCyihzhKpDAoTPAd3pVcob5m9nWKOlG8NFyKaBpdCn4HhhcIJkOY5XIBfjgwEwObaRQGlhkK4AYE",
+	"3qKqB0RxNJeqoFUjLszrl1Fcy+bC4AIVCedhfRXecm1VWNdo4qQlCpnBNIZEVsJoqEqQAkj+CjDlJijM",
+	"sIXeXPEiQ3hGr0DO7Ta9sWLI5RJVwjSmsOQmk5Wx759FccQNFjqoun/AlGIr+9sO28EUlUYlWIGBVa1F",
+	"biquMI2mn8lorUtj7/lr+7RZxAruGNLv/qqRLGd/YGJIMOHplM/nm5iaK1l01Okom3PhhjSG+IfCeTSN",
+	"no1a3I48aEe1hPdcYNBGMiRlbddWGTu2Fv/YZqyojQ3Jkv5FURW0It5ULCdnCo2KLJlijgY763bcindm",
+	"u2dkGfmhQ7p9YIuAXikzbC9rhqwo8M5cJ5XSUm2i/I19DnOpLIZpLJRsgU0QS2Ff5Ey7F1G8Za9W56Ft",
+	"nncCuL/VR6ko5QbTrUzEdR2jsGQaFJY5SzDdjXG61LIFcZ3gaaOt1XHb3t9zbZ7A1Y0pN1y+q0PeWLJ0",
+	"a91UGNJqlwOCB+j4IyZSpBoqYXgeOCRECpw8VMhbYuuqBCPheAwrZEofwdnaoeHI3SHyKIqjgt3xgoL1",
+	"9YvxT8fj8XgcRwUX7tkk3ubAelcho7yXCy7OUZdS6EBEGvkFA/v95dMF2Fc2koh5URieMOM5dt12xMfb",
+	"nHypA6o7+X6BQf0v/fJ93Uum9VIqe7QW7O49ioXJounEW6/+/XpA3/oQ6kx91Z/5Yhs3dI6hRpnQJs5x",
+	"wbVBFd5HQJHJeJsm8Xewfb/1Xa1wwRZvKI3ZtAAFUyBloVDWsFTcGBTAHXMvuUjlEkzGDFTaZWyGLXZj",
+	"RRq49XRzqzmdHtvHExBfY5KvJr3LMv160tuDR8LQ9fmpP8q+PhEeSAj7IN942ZDXJsDLdD+99sxKH0F+",
+	"3HBaxzg9jYbM+0HJOc8xHB7XSR06oRihICBlIGNNwMRQMC4M4wJTmK1cCYFqx4rl4J790xbvWGXTog9x",
+	"pDGpFDerjxRrzpAnJf8VVycVsdt9xMl+GbIUVS1gGv33+cmHs+e//vt/7SaZnWXTHmQKVT1/Zn+9rU3z",
+	"y6eLKHYFLM1yb9tVMmPK6IEU42Iu67hkibUvFozn0TRSfP6Fs1SJyfG/FvTsKJFFq9w5vYaTVHFm86Q+",
+	"FH4vUcDJhzPQJSZ87o9rWGY8ycANnaHDCo2ik/0N05qJVDH4oKS1XBwZbgiEUejdLSrtC9Sj8dGYdJAl",
+	"ClbyaBq9OJocjW0omMyae0SJwyinE5x+llIHAHzSJhcew5RNKTSVEtDkIZQlUUjYLVH130kMHFpQm59l",
+	"ulojPFaWuTfE6A/t0mFHvtuouV3/oQ9Ioyq0D1xOZXd6PB4/reAmY7PC+xazA0BXSYJaz6v8iNzw0mnQ",
+	"H3kmblnOU+CirIwbNRkelShMyRMs1zT2VXhFQwGYg0ZlE1qlpOqFWzT9fBVHuioKplaNtlWd/I2as36B",
+	"1lB9r1o6sxhSrEBLV9PP60r85tJlEFUxQ0X9jJljQelxc2Sr3Wga3VSoVm385LywPZPWESnOWZWbaHo8",
+	"7qThk+0J+P1G8LGbCsHVpV4NTIFp6NSrRMMUfiWVXLLStgIdUtZN6Wm7zpNXB4RhU8UHEHhiFW8MH4PA",
+	"JWoDc6602YpG6wWQyhvLoafByzs0wPLcrUxr1bTRB0pb7B0o/jeryZ14YPKkDggZn57XjcE12zmdgVnj",
+	"dcJtpJGpJOtEXSiJ8H3BTrWHacvI9lXi+5AmwxXMZZ7Lpe1RMC64WPjGJKVBtNhNDDNCRcFMkjlsbNL4",
+	"R6vZTmH/SarUBrnbDR1gsVscU0iYxudcaBSaG36L+Woorm6idS92Q6xTDh2/et2rhyaBeugHNX231GSR",
+	"Qaj0HLWOxUGe+o1rTfPspmPgQ7RFR+Qk1Cuy6OQUUPO4k2I5JDCFIEW+8ihmsxxt293/PsJihmmK6TUX",
+	"Kd45KS/C7fx6aB0QdgYJ1oYTgyLtQuGs4rlZYwqvpCfZlijuefrghNkW8YbY30XucOK68ZAwAW6sZx3b",
+	"7OIaWK5l3RAD6mnbWYYXaJvawF1Tc84EYdHeOchNdji1Szcs38PTyzCPeXXSrccQaQtnp27cgIUTlueo",
+	"aD9Cms6+3aQhDWjsXFZinZ5Pu4aiJYJkHDKxQpb6eRSo3Gg4O9001zs0YVuND34qXWTYbOs7M7vNKBrl",
+	"Hj1izk7rU9BiuaY/qmha9uPpoydIgAlLYp3dPO36BE0wnQjXM655DL4gltoCwD5fbYKg7QgdKDPabDl9",
+	"4wrpMQz6PsteWAR7LNqtwIws9e2wedl19zoRj+qbGT2YuV20F7k1P2QshRnOpUJAlmT2nrifoR+BnVYp",
+	"RdNqIbSl5maDG435fJBhzhvFDuzm3iXTgMublKUxVzeIv0NCos3YRXoK/5UUNYS7UeovzIPgOwE6y4HG",
+	"ACWVLt3waLJnPhdGto+MjAG5yVBBwVYwc837dRwGsgA+n6+j7lEbnbcSnXakzFAy6y/cHykJ9sq2N2Qb",
+	"OSTZyK+Xe+j8mmw+FHCN1/chWNbBQc0yqUQXURm7xQMEoFSt0MGkzPpoKb+vYDRsoUdGloOxd9H5kmcW",
+	"uiezXzhkslI6hkJqe1mWDlXh79BcyJLW3BZarhiuJfvLOC5qSe6SIYUZS744ChByOYR/O2eg5n3ZqXl/",
+	"Ot675t2sw8mif6oMn+xXhh8yQHs3kANB2vrc7tyWmCZDrlzoWUfprQHsHSyVK3+HDrK+MJfHWAjfG7Z4",
+	"2Oj5PmH3qbKlur8EXktzgjBnix895r9XI6elODkPe3orjO2Mx/vODZYbUYymbT0CLpxCzaeT3Gh4FkOO",
+	"hibEkPIFPSIQX7soZAZyJHaWAv24gZPDfYmwU7Py9cttvUp3rvgGzEGCcVv4XdTCf8Te3yn2asw8+TUP",
+	"LZ7JopXgDg7CmR6+MG4uOQQuPSYdFjcB1/vu6jDNkJ6Ib3xD1Mpc+4iUjOJviDr3xHQlssdN8T83R136",
+	"ry+A5dSRXAHecW2e8ra4NmfHvR1QNO3p4N3xOzSNow8UNN3PdIYaUGT80o1xfE/03+ZcW11gFzg7Haxs",
+	"rHcf73V2ddijhqFpT1jDtC4bufOhf7Gw1rUUbkzYgwEz/EdCLsUClT986jTQQWY3G4P/TNz+9O0vV2l+",
+	"nfXrTXgX7Gl6Ot/8fKmg8ms9XRM6eI/+dj+rv92wddyxP1swbvuIDIR8Lsu/yg9v+15YI5z73ndcn68e",
+	"4v6XYZ+vyFyOspzXKpVH02gUPVw9/H8A674Df7E0AAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
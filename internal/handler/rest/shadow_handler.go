@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
+)
+
+type IShadowUseCase interface {
+	Stats(ctx context.Context) (map[string]entity.ShadowStats, error)
+}
+
+type ShadowHandler struct {
+	Log     *logrus.Logger
+	UseCase IShadowUseCase
+}
+
+func NewShadowHandler(useCase IShadowUseCase, logger *logrus.Logger) *ShadowHandler {
+	return &ShadowHandler{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Stats reports shadow read comparisons per resource
+func (h *ShadowHandler) Stats(c *fiber.Ctx) error {
+	stats, err := h.UseCase.Stats(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": stats,
+	})
+}
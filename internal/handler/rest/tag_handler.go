@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	model "github.com/rifkiadrn/cassandra-explore/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type ITagUseCase interface {
+	GetTagBlogs(ctx context.Context, tag string, page entity.Page) ([]entity.Blog, string, error)
+	GetTopTags(ctx context.Context, window time.Duration, limit int) ([]entity.TagCount, error)
+}
+
+type TagHandler struct {
+	Log     *logrus.Logger
+	UseCase ITagUseCase
+}
+
+func NewTagHandler(useCase ITagUseCase, logger *logrus.Logger) *TagHandler {
+	return &TagHandler{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (h *TagHandler) GetTagBlogs(c *fiber.Ctx, tag string, params model.GetTagBlogsParams) error {
+	page := entity.Page{}
+	if params.Limit != nil {
+		page.Limit = *params.Limit
+	}
+	if params.Cursor != nil {
+		page.Cursor = *params.Cursor
+	}
+
+	blogs, nextCursor, err := h.UseCase.GetTagBlogs(c.Context(), tag, page)
+	if err != nil {
+		return err
+	}
+
+	blogsResponse := make([]model.Blog, 0, len(blogs))
+	for _, blog := range blogs {
+		blogsResponse = append(blogsResponse, convertToBlogResponse(blog))
+	}
+
+	response := model.BlogPage{
+		Data: blogsResponse,
+	}
+	if nextCursor != "" {
+		response.NextCursor = &nextCursor
+	}
+
+	return c.JSON(response)
+}
+
+func (h *TagHandler) GetTopTags(c *fiber.Ctx, params model.GetTopTagsParams) error {
+	var window time.Duration
+	if params.Hours != nil {
+		window = time.Duration(*params.Hours) * time.Hour
+	}
+	limit := 0
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	tags, err := h.UseCase.GetTopTags(c.Context(), window, limit)
+	if err != nil {
+		return err
+	}
+
+	response := model.TagCountList{
+		Data: make([]model.TagCount, 0, len(tags)),
+	}
+	for _, tag := range tags {
+		response.Data = append(response.Data, model.TagCount{
+			Tag:   tag.Tag,
+			Blogs: tag.Blogs,
+		})
+	}
+
+	return c.JSON(response)
+}
@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	model "github.com/rifkiadrn/cassandra-explore/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type ITimelineUseCase interface {
+	GetTimeline(ctx context.Context, page entity.Page) ([]entity.Blog, string, error)
+	Follow(ctx context.Context, userID string) error
+	Unfollow(ctx context.Context, userID string) error
+}
+
+type TimelineHandler struct {
+	Log     *logrus.Logger
+	UseCase ITimelineUseCase
+}
+
+func NewTimelineHandler(useCase ITimelineUseCase, logger *logrus.Logger) *TimelineHandler {
+	return &TimelineHandler{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (h *TimelineHandler) Timeline(c *fiber.Ctx, params model.TimelineParams) error {
+	page := entity.Page{}
+	if params.Limit != nil {
+		page.Limit = *params.Limit
+	}
+	if params.Cursor != nil {
+		page.Cursor = *params.Cursor
+	}
+
+	blogs, nextCursor, err := h.UseCase.GetTimeline(c.Context(), page)
+	if err != nil {
+		return err
+	}
+
+	blogsResponse := make([]model.Blog, 0, len(blogs))
+	for _, blog := range blogs {
+		blogsResponse = append(blogsResponse, convertToBlogResponse(blog))
+	}
+
+	response := model.BlogPage{
+		Data: blogsResponse,
+	}
+	if nextCursor != "" {
+		response.NextCursor = &nextCursor
+	}
+
+	return c.JSON(response)
+}
+
+func (h *TimelineHandler) FollowUser(c *fiber.Ctx, id string) error {
+	if err := h.UseCase.Follow(c.Context(), id); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *TimelineHandler) UnfollowUser(c *fiber.Ctx, id string) error {
+	if err := h.UseCase.Unfollow(c.Context(), id); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -11,6 +11,7 @@ import (
 type IUserUseCase interface {
 	Register(ctx context.Context, request model.RegisterUser) (model.User, error)
 	Login(ctx context.Context, request model.LoginUser) (model.LoginResponse, error)
+	GetUser(ctx context.Context, userID string) (model.UserProfile, error)
 }
 
 type UserHandler struct {
@@ -50,3 +51,12 @@ func (h *UserHandler) LoginUser(c *fiber.Ctx) error {
 
 	return c.JSON(response)
 }
+
+func (h *UserHandler) GetUser(c *fiber.Ctx, id string) error {
+	profile, err := h.UseCase.GetUser(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(profile)
+}
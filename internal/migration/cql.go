@@ -0,0 +1,258 @@
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Migration statuses reported by Status
+const (
+	StatusPending  = "pending"
+	StatusPartial  = "partial" // Some statements ran before a failure, Up resumes after them
+	StatusApplied  = "applied"
+	StatusModified = "modified" // Applied, but the file changed since
+)
+
+const (
+	migrationsTable = "cql_schema_migrations"
+	upMarker        = "-- migrate:up"
+	downMarker      = "-- migrate:down"
+)
+
+// Migration is one versioned .cql file, named <version>_<name>.cql like the dbmate migrations
+type Migration struct {
+	Version    string    `json:"version"`
+	Name       string    `json:"name"`
+	Checksum   string    `json:"checksum"`
+	Statements []string  `json:"-"`
+	Applied    int       `json:"applied"` // Statements already applied
+	AppliedAt  time.Time `json:"applied_at,omitempty"`
+	Status     string    `json:"status"`
+}
+
+// CQLMigrator applies the CQL migrations of a directory and records them in the cql_schema_migrations table
+type CQLMigrator struct {
+	newCluster  func() *gocql.ClusterConfig // A fresh config per session, gocql host policies cannot be shared
+	dir         string
+	replication string
+	log         *logrus.Logger
+}
+
+func NewCQLMigrator(newCluster func() *gocql.ClusterConfig, dir string, replication string, log *logrus.Logger) *CQLMigrator {
+	return &CQLMigrator{
+		newCluster:  newCluster,
+		dir:         dir,
+		replication: replication,
+		log:         log,
+	}
+}
+
+// Up applies every pending migration in version order
+func (m *CQLMigrator) Up(ctx context.Context) ([]Migration, error) {
+	session, err := m.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	migrations, err := m.status(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, migration := range migrations {
+		switch migration.Status {
+		case StatusApplied:
+			continue
+		case StatusModified:
+			m.log.Warnf("CQL migration %s_%s changed after it was applied, skipping", migration.Version, migration.Name)
+			continue
+		}
+
+		m.log.Infof("Applying CQL migration %s_%s", migration.Version, migration.Name)
+		// resume a partial migration after its last applied statement, ALTER TABLE ADD cannot run twice
+		for i := migration.Applied; i < len(migration.Statements); i++ {
+			if err := session.Query(migration.Statements[i]).ExecContext(ctx); err != nil {
+				return applied, fmt.Errorf("migration %s statement %d: %w", migration.Version, i+1, err)
+			}
+			if err := session.AwaitSchemaAgreement(ctx); err != nil {
+				return applied, fmt.Errorf("migration %s statement %d schema agreement: %w", migration.Version, i+1, err)
+			}
+
+			migration.Applied = i + 1
+			migration.AppliedAt = time.Now()
+			if err := session.Query(`INSERT INTO `+migrationsTable+` (version, name, checksum, statements, applied, applied_at) VALUES (?, ?, ?, ?, ?, ?)`,
+				migration.Version, migration.Name, migration.Checksum, len(migration.Statements), migration.Applied, migration.AppliedAt).ExecContext(ctx); err != nil {
+				return applied, err
+			}
+		}
+
+		migration.Status = StatusApplied
+		applied = append(applied, migration)
+	}
+
+	return applied, nil
+}
+
+// Status lists every migration with its state
+func (m *CQLMigrator) Status(ctx context.Context) ([]Migration, error) {
+	session, err := m.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	return m.status(ctx, session)
+}
+
+func (m *CQLMigrator) status(ctx context.Context, session *gocql.Session) ([]Migration, error) {
+	migrations, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+
+	type record struct {
+		checksum  string
+		applied   int
+		appliedAt time.Time
+	}
+	records := map[string]record{}
+
+	iter := session.Query(`SELECT version, checksum, applied, applied_at FROM ` + migrationsTable).IterContext(ctx)
+	var (
+		version string
+		rec     record
+	)
+	for iter.Scan(&version, &rec.checksum, &rec.applied, &rec.appliedAt) {
+		records[version] = rec
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	for i := range migrations {
+		rec, ok := records[migrations[i].Version]
+		switch {
+		case !ok:
+			migrations[i].Status = StatusPending
+		case rec.checksum != migrations[i].Checksum:
+			migrations[i].Status = StatusModified
+		case rec.applied < len(migrations[i].Statements):
+			migrations[i].Status = StatusPartial
+		default:
+			migrations[i].Status = StatusApplied
+		}
+		migrations[i].Applied = rec.applied
+		migrations[i].AppliedAt = rec.appliedAt
+	}
+
+	return migrations, nil
+}
+
+// connect creates the keyspace and tracking table if needed and opens a session on the keyspace
+func (m *CQLMigrator) connect(ctx context.Context) (*gocql.Session, error) {
+	cluster := m.newCluster()
+	keyspace := cluster.Keyspace
+
+	// the keyspace may not exist yet, so the first session is not bound to it
+	bootstrap := m.newCluster()
+	bootstrap.Keyspace = ""
+	bootstrapSession, err := bootstrap.CreateSession()
+	if err != nil {
+		return nil, err
+	}
+	defer bootstrapSession.Close()
+
+	if err := bootstrapSession.Query(fmt.Sprintf(`CREATE KEYSPACE IF NOT EXISTS %s WITH replication = %s`, keyspace, m.replication)).ExecContext(ctx); err != nil {
+		return nil, err
+	}
+	if err := bootstrapSession.Query(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.%s (
+		version text PRIMARY KEY,
+		name text,
+		checksum text,
+		statements int,
+		applied int,
+		applied_at timestamp
+	)`, keyspace, migrationsTable)).ExecContext(ctx); err != nil {
+		return nil, err
+	}
+	if err := bootstrapSession.AwaitSchemaAgreement(ctx); err != nil {
+		return nil, err
+	}
+
+	return cluster.CreateSession()
+}
+
+// load reads the migration files in version order
+func (m *CQLMigrator) load() ([]Migration, error) {
+	files, err := filepath.Glob(filepath.Join(m.dir, "*.cql"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	migrations := make([]Migration, 0, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		version, name, ok := strings.Cut(strings.TrimSuffix(filepath.Base(file), ".cql"), "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s is not named <version>_<name>.cql", file)
+		}
+
+		sum := sha256.Sum256(content)
+		migrations = append(migrations, Migration{
+			Version:    version,
+			Name:       name,
+			Checksum:   hex.EncodeToString(sum[:]),
+			Statements: splitStatements(string(content)),
+		})
+	}
+
+	return migrations, nil
+}
+
+// splitStatements returns the statements of the up section, split on a trailing semicolon
+func splitStatements(content string) []string {
+	if _, up, ok := strings.Cut(content, upMarker); ok {
+		content = up
+	}
+	content, _, _ = strings.Cut(content, downMarker)
+
+	var (
+		statements []string
+		current    strings.Builder
+	)
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if strings.TrimSpace(current.String()) != "" {
+		statements = append(statements, strings.TrimSpace(current.String()))
+	}
+
+	return statements
+}
@@ -5,9 +5,12 @@ import (
 )
 
 type Blog struct {
-	ID       uuid.UUID `gorm:"column:id;primaryKey;default:gen_random_uuid()"` // Auto-generate UUID
-	Content  string    `gorm:"column:content;not null"`
-	AuthorID uuid.UUID `gorm:"column:user_id;not null"`
-	Username string    `gorm:"column:username;not null"`
-	Ts       int64     `gorm:"column:ts;autoCreateTime"`
+	ID        uuid.UUID `gorm:"column:id;primaryKey;default:gen_random_uuid()"` // Auto-generate UUID
+	Content   string    `gorm:"column:content;not null"`
+	AuthorID  uuid.UUID `gorm:"column:user_id;not null"`
+	Username  string    `gorm:"column:username;not null"`
+	Ts        int64     `gorm:"column:ts;autoCreateTime"`
+	ExpiresAt *int64    `gorm:"column:expires_at"` // NULL when the blog never expires
+	DeletedAt *int64    `gorm:"column:deleted_at"` // Set when the blog is deleted, the row is kept
+	Revision  int       `gorm:"column:revision;not null;default:1"`
 }
@@ -0,0 +1,14 @@
+package model_db
+
+import (
+	"github.com/google/uuid"
+)
+
+// BlogRevision is the content of a blog before one of its edits
+type BlogRevision struct {
+	BlogID    uuid.UUID `gorm:"column:blog_id;primaryKey"`
+	Revision  int       `gorm:"column:revision;primaryKey"`
+	Content   string    `gorm:"column:content;not null"`
+	EditedAt  int64     `gorm:"column:edited_at;not null"`
+	ExpiresAt *int64    `gorm:"column:expires_at"` // NULL when the blog never expires
+}
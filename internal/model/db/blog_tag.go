@@ -0,0 +1,12 @@
+package model_db
+
+import (
+	"github.com/google/uuid"
+)
+
+// BlogTag files a blog under one of its #tags, at the blog's time
+type BlogTag struct {
+	BlogID uuid.UUID `gorm:"column:blog_id;primaryKey"`
+	Tag    string    `gorm:"column:tag;primaryKey"`
+	Ts     int64     `gorm:"column:ts;not null"`
+}
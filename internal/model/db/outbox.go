@@ -0,0 +1,23 @@
+package model_db
+
+import (
+	"github.com/google/uuid"
+)
+
+// OutboxEvent represents the database model for outbox rows
+type OutboxEvent struct {
+	ID            uuid.UUID `gorm:"column:id;primaryKey;default:gen_random_uuid()"` // Auto-generate UUID
+	AggregateID   uuid.UUID `gorm:"column:aggregate_id;not null"`
+	EventType     string    `gorm:"column:event_type;not null"`
+	Payload       string    `gorm:"column:payload;type:jsonb;not null"`
+	Status        string    `gorm:"column:status;not null"`
+	Attempts      int       `gorm:"column:attempts;not null"`
+	LastError     string    `gorm:"column:last_error;not null"`
+	NextAttemptAt int64     `gorm:"column:next_attempt_at;not null"`
+	CreatedAt     int64     `gorm:"column:created_at;autoCreateTime"`                // Auto-generated
+	UpdatedAt     int64     `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"` // Auto-generated
+}
+
+func (o *OutboxEvent) TableName() string {
+	return "cassandra_users.outbox"
+}
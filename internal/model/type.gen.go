@@ -8,18 +8,75 @@ const (
 	BearerAuthScopes = "BearerAuth.Scopes"
 )
 
+// Defines values for BlogDiffLineOp.
+const (
+	Delete BlogDiffLineOp = "delete"
+	Equal  BlogDiffLineOp = "equal"
+	Insert BlogDiffLineOp = "insert"
+)
+
 // Blog defines model for Blog.
 type Blog struct {
 	AuthorId *string `json:"authorId,omitempty"`
 	Content  string  `json:"content"`
-	Id       string  `json:"id"`
-	Ts       int64   `json:"ts"`
-	Username string  `json:"username"`
+
+	// ExpiresAt Unix time the blog expires at, absent when it never expires
+	ExpiresAt *int64 `json:"expires_at,omitempty"`
+	Id        string `json:"id"`
+
+	// Revision 1 when created, counts up on every edit
+	Revision int `json:"revision"`
+
+	// Tags The #tags of the content, lowercased without the #
+	Tags     []string `json:"tags"`
+	Ts       int64    `json:"ts"`
+	Username string   `json:"username"`
+}
+
+// BlogDiff defines model for BlogDiff.
+type BlogDiff struct {
+	From  int            `json:"from"`
+	Lines []BlogDiffLine `json:"lines"`
+	To    int            `json:"to"`
+}
+
+// BlogDiffLine defines model for BlogDiffLine.
+type BlogDiffLine struct {
+	Op   BlogDiffLineOp `json:"op"`
+	Text string         `json:"text"`
+}
+
+// BlogDiffLineOp defines model for BlogDiffLine.Op.
+type BlogDiffLineOp string
+
+// BlogPage defines model for BlogPage.
+type BlogPage struct {
+	Data []Blog `json:"data"`
+
+	// NextCursor Cursor for the next page, absent on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// BlogRevision defines model for BlogRevision.
+type BlogRevision struct {
+	Content string `json:"content"`
+
+	// EditedAt Unix time this content was replaced
+	EditedAt int64 `json:"edited_at"`
+	Revision int   `json:"revision"`
+}
+
+// BlogRevisionList defines model for BlogRevisionList.
+type BlogRevisionList struct {
+	Data []BlogRevision `json:"data"`
 }
 
 // CreateBlogRequest defines model for CreateBlogRequest.
 type CreateBlogRequest struct {
 	Content string `json:"content"`
+
+	// ExpiresIn Seconds until the blog expires and is removed, up to 20 years. It never expires when absent.
+	ExpiresIn *int `json:"expires_in,omitempty"`
 }
 
 // LoginResponse defines model for LoginResponse.
@@ -42,6 +99,23 @@ type RegisterUser struct {
 	Username string `json:"username"`
 }
 
+// TagCount defines model for TagCount.
+type TagCount struct {
+	// Blogs Blogs written in the window that use the tag
+	Blogs int64  `json:"blogs"`
+	Tag   string `json:"tag"`
+}
+
+// TagCountList defines model for TagCountList.
+type TagCountList struct {
+	Data []TagCount `json:"data"`
+}
+
+// UpdateBlogRequest defines model for UpdateBlogRequest.
+type UpdateBlogRequest struct {
+	Content string `json:"content"`
+}
+
 // User defines model for User.
 type User struct {
 	CreatedAt int64  `json:"created_at"`
@@ -53,11 +127,81 @@ type User struct {
 	Username  string `json:"username"`
 }
 
+// UserProfile defines model for UserProfile.
+type UserProfile struct {
+	// BlogCount Blogs the user has written, maintained by counters
+	BlogCount int64  `json:"blog_count"`
+	CreatedAt *int64 `json:"created_at,omitempty"`
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	Username  string `json:"username"`
+}
+
+// BlogsParams defines parameters for Blogs.
+type BlogsParams struct {
+	// Limit Maximum number of blogs to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor returned as next_cursor by the previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// SearchBlogsParams defines parameters for SearchBlogs.
+type SearchBlogsParams struct {
+	// Q Words to search for, matched case-insensitively.
+	Q string `form:"q" json:"q"`
+
+	// Limit Maximum number of blogs to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor returned as next_cursor by the previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// DiffBlogRevisionsParams defines parameters for DiffBlogRevisions.
+type DiffBlogRevisionsParams struct {
+	// From Revision to diff from.
+	From int `form:"from" json:"from"`
+
+	// To Revision to diff to.
+	To int `form:"to" json:"to"`
+}
+
+// GetTopTagsParams defines parameters for GetTopTags.
+type GetTopTagsParams struct {
+	// Hours Length of the window in hours, counted back from now.
+	Hours *int `form:"hours,omitempty" json:"hours,omitempty"`
+
+	// Limit Maximum number of tags to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetTagBlogsParams defines parameters for GetTagBlogs.
+type GetTagBlogsParams struct {
+	// Limit Maximum number of blogs to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor returned as next_cursor by the previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// TimelineParams defines parameters for Timeline.
+type TimelineParams struct {
+	// Limit Maximum number of blogs to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor returned as next_cursor by the previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
 // LoginUserJSONRequestBody defines body for LoginUser for application/json ContentType.
 type LoginUserJSONRequestBody = LoginUser
 
 // CreateBlogJSONRequestBody defines body for CreateBlog for application/json ContentType.
 type CreateBlogJSONRequestBody = CreateBlogRequest
 
+// UpdateBlogJSONRequestBody defines body for UpdateBlog for application/json ContentType.
+type UpdateBlogJSONRequestBody = UpdateBlogRequest
+
 // RegisterUserJSONRequestBody defines body for RegisterUser for application/json ContentType.
 type RegisterUserJSONRequestBody = RegisterUser
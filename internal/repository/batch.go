@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+)
+
+// Statement idempotency for queryNoSQL and execNoSQL, only idempotent statements are retried
+const (
+	idempotent    = true
+	notIdempotent = false
+)
+
+// NoSQLSession is a Cassandra session with the speculative execution policy of its idempotent statements
+type NoSQLSession struct {
+	*gocql.Session
+	speculativeExecution gocql.SpeculativeExecutionPolicy
+}
+
+// NewNoSQLSession pairs a session with a speculative execution policy, nil never speculates
+func NewNoSQLSession(session *gocql.Session, speculativeExecution gocql.SpeculativeExecutionPolicy) NoSQLSession {
+	return NoSQLSession{
+		Session:              session,
+		speculativeExecution: speculativeExecution,
+	}
+}
+
+// queryNoSQL builds a query with the consistency and tracer of ctx, if any
+func queryNoSQL(ctx context.Context, db NoSQLSession, isIdempotent bool, stmt string, values ...interface{}) *gocql.Query {
+	query := db.Query(stmt, values...).Idempotent(isIdempotent)
+	if isIdempotent && db.speculativeExecution != nil {
+		query = query.SetSpeculativeExecutionPolicy(db.speculativeExecution)
+	}
+	if consistency, ok := context_db.GetConsistency(ctx); ok {
+		query = query.Consistency(consistency)
+	}
+	if tracer, ok := context_db.GetTracer(ctx); ok {
+		query = query.Trace(tracer)
+	}
+	return query
+}
+
+// execNoSQL runs a write right away, or queues it on the Cassandra batch of ctx
+func execNoSQL(ctx context.Context, db NoSQLSession, isIdempotent bool, stmt string, values ...interface{}) error {
+	if tx := context_db.GetBatch(ctx); tx != nil {
+		return tx.Add(isIdempotent, stmt, values...)
+	}
+	return queryNoSQL(ctx, db, isIdempotent, stmt, values...).ExecContext(ctx)
+}
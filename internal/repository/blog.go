@@ -2,12 +2,15 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
 	"github.com/rifkiadrn/cassandra-explore/internal/entity"
 	model_db "github.com/rifkiadrn/cassandra-explore/internal/model/db"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BlogRepository struct {
@@ -24,29 +27,55 @@ func NewBlogRepository(db *gorm.DB, log *logrus.Logger) BlogRepository {
 
 func (r *BlogRepository) getDB(ctx context.Context) *gorm.DB {
 	if tx := context_db.GetTx(ctx); tx != nil {
-		return tx
+		return tx.WithContext(ctx)
 	}
-	return r.db
+	return r.db.WithContext(ctx)
 }
 
 // entityToDBBlog converts domain entity to DB model
 func (r BlogRepository) entityToDBBlog(e entity.Blog) model_db.Blog {
-	return model_db.Blog{
+	dbBlog := model_db.Blog{
 		ID:       e.ID,
 		AuthorID: e.AuthorID,
 		Username: e.Username,
 		Content:  e.Content,
+		Revision: e.Revision, // 0 takes the column default of 1
 	}
+	// keep the original time when copying a blog in, otherwise ts is auto-generated by GORM
+	if !e.Ts.IsZero() {
+		dbBlog.Ts = e.Ts.Unix()
+	}
+	if !e.ExpiresAt.IsZero() {
+		expiresAt := e.ExpiresAt.Unix()
+		dbBlog.ExpiresAt = &expiresAt
+	}
+	return dbBlog
 }
 
 // dbToEntityBlog converts DB model to domain entity pointer
 func (r BlogRepository) dbToEntityBlog(db model_db.Blog) *entity.Blog {
-	return &entity.Blog{
+	blog := &entity.Blog{
 		ID:       db.ID,
 		AuthorID: db.AuthorID,
 		Username: db.Username,
 		Content:  db.Content,
+		Ts:       time.Unix(db.Ts, 0),
+		Revision: db.Revision,
+	}
+	if db.ExpiresAt != nil {
+		blog.ExpiresAt = time.Unix(*db.ExpiresAt, 0)
 	}
+	return blog
+}
+
+// notExpired hides expired blogs the sweeper has not deleted yet
+func notExpired(db *gorm.DB) *gorm.DB {
+	return db.Where("(expires_at IS NULL OR expires_at > ?)", time.Now().Unix())
+}
+
+// notDeleted hides soft deleted blogs
+func notDeleted(db *gorm.DB) *gorm.DB {
+	return db.Where("deleted_at IS NULL")
 }
 
 // Create creates a new blog
@@ -60,10 +89,109 @@ func (r BlogRepository) Create(ctx context.Context, blog entity.Blog) (*entity.B
 	return r.dbToEntityBlog(dbBlog), nil
 }
 
-// FindAll finds all blogs for a user
-func (r BlogRepository) FindAll(ctx context.Context, userID string) ([]*entity.Blog, error) {
+// FindAll finds one page of blogs for a user, newest first, using a (ts, id) keyset
+func (r BlogRepository) FindAll(ctx context.Context, userID string, page entity.Page) ([]*entity.Blog, string, error) {
+	after, err := entity.DecodeFeedPosition(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := r.getDB(ctx).Scopes(notDeleted, notExpired).Where("user_id = ?", userID)
+	if !after.IsZero() {
+		query = query.Where("(ts, id) < (?, ?)", after.Ts.Unix(), after.ID)
+	}
+
+	// fetch one extra row to know whether there is a next page
+	var dbBlogs []model_db.Blog
+	if err := query.Order("ts DESC, id DESC").Limit(page.Limit + 1).Find(&dbBlogs).Error; err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(dbBlogs) > page.Limit {
+		dbBlogs = dbBlogs[:page.Limit]
+		last := dbBlogs[len(dbBlogs)-1]
+		nextCursor = entity.FeedPosition{Ts: time.Unix(last.Ts, 0), ID: last.ID}.Encode()
+	}
+
+	// Convert to entities
+	blogs := make([]*entity.Blog, len(dbBlogs))
+	for i, dbBlog := range dbBlogs {
+		blogs[i] = r.dbToEntityBlog(dbBlog)
+	}
+
+	return blogs, nextCursor, nil
+}
+
+// FindBefore finds up to limit of a user's blogs older than the position, newest first
+func (r BlogRepository) FindBefore(ctx context.Context, userID string, before entity.FeedPosition, limit int) ([]*entity.Blog, error) {
+	query := r.getDB(ctx).Scopes(notDeleted, notExpired).Where("user_id = ?", userID)
+	if !before.IsZero() {
+		query = query.Where("(ts, id) < (?, ?)", before.Ts.Unix(), before.ID)
+	}
+
+	var dbBlogs []model_db.Blog
+	if err := query.Order("ts DESC, id DESC").Limit(limit).Find(&dbBlogs).Error; err != nil {
+		return nil, err
+	}
+
+	blogs := make([]*entity.Blog, len(dbBlogs))
+	for i, dbBlog := range dbBlogs {
+		blogs[i] = r.dbToEntityBlog(dbBlog)
+	}
+
+	return blogs, nil
+}
+
+// FindById finds a blog that is neither deleted nor expired
+func (r BlogRepository) FindById(ctx context.Context, blogID string) (*entity.Blog, error) {
+	var dbBlog model_db.Blog
+	if err := r.getDB(ctx).Scopes(notDeleted, notExpired).Where("id = ?", blogID).First(&dbBlog).Error; err != nil {
+		return nil, notFound(err)
+	}
+
+	return r.dbToEntityBlog(dbBlog), nil
+}
+
+// Update rewrites the content of a blog, an edit not on top of the previous revision is entity.ErrConflict
+func (r BlogRepository) Update(ctx context.Context, blog entity.Blog) (*entity.Blog, error) {
+	result := r.getDB(ctx).Model(&model_db.Blog{}).Scopes(notDeleted).Where("id = ? AND revision = ?", blog.ID, blog.Revision-1).
+		Updates(map[string]interface{}{"content": blog.Content, "revision": blog.Revision})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		if _, err := r.FindById(ctx, blog.ID.String()); err != nil {
+			return nil, err
+		}
+		return nil, entity.ErrConflict
+	}
+
+	return &blog, nil
+}
+
+// Delete soft deletes a blog, the row stays with its deletion time
+func (r BlogRepository) Delete(ctx context.Context, blogID string) error {
+	result := r.getDB(ctx).Model(&model_db.Blog{}).Scopes(notDeleted).Where("id = ?", blogID).Update("deleted_at", time.Now().Unix())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+// FindBatch finds up to limit unexpired blogs with an ID greater than afterID, in ID order
+func (r BlogRepository) FindBatch(ctx context.Context, afterID string, limit int) ([]*entity.Blog, error) {
+	query := r.getDB(ctx).Scopes(notDeleted, notExpired)
+	if afterID != "" {
+		query = query.Where("id > ?", afterID)
+	}
+
 	var dbBlogs []model_db.Blog
-	if err := r.getDB(ctx).Where("user_id = ?", userID).Find(&dbBlogs).Error; err != nil {
+	if err := query.Order("id").Limit(limit).Find(&dbBlogs).Error; err != nil {
 		return nil, err
 	}
 
@@ -75,3 +203,77 @@ func (r BlogRepository) FindAll(ctx context.Context, userID string) ([]*entity.B
 
 	return blogs, nil
 }
+
+// FindPage pages every unexpired blog in ID order
+func (r BlogRepository) FindPage(ctx context.Context, page entity.Page) ([]*entity.Blog, string, error) {
+	blogs, err := r.FindBatch(ctx, page.Cursor, page.Limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(blogs) == page.Limit {
+		nextCursor = blogs[len(blogs)-1].ID.String()
+	}
+
+	return blogs, nextCursor, nil
+}
+
+// DeleteExpired deletes up to limit blogs that expired at or before now and returns them
+func (r BlogRepository) DeleteExpired(ctx context.Context, now time.Time, limit int) ([]*entity.Blog, error) {
+	expired := r.getDB(ctx).Model(&model_db.Blog{}).Scopes(notDeleted).Select("id").Where("expires_at <= ?", now.Unix()).Limit(limit)
+
+	var dbBlogs []model_db.Blog
+	if err := r.getDB(ctx).Clauses(clause.Returning{}).Where("id IN (?)", expired).Delete(&dbBlogs).Error; err != nil {
+		return nil, err
+	}
+
+	blogs := make([]*entity.Blog, len(dbBlogs))
+	for i, dbBlog := range dbBlogs {
+		blogs[i] = r.dbToEntityBlog(dbBlog)
+	}
+
+	return blogs, nil
+}
+
+// CountByAuthor counts an author's blogs, expired ones included until they are swept
+func (r BlogRepository) CountByAuthor(ctx context.Context, authorID string) (int64, error) {
+	var count int64
+	err := r.getDB(ctx).Model(&model_db.Blog{}).Scopes(notDeleted).Where("user_id = ?", authorID).Count(&count).Error
+	return count, err
+}
+
+// Search finds one page of the authors' blogs having every word of the query, ranked by ts_rank
+func (r BlogRepository) Search(ctx context.Context, query string, authorIDs []uuid.UUID, page entity.Page) ([]*entity.Blog, string, error) {
+	offset, err := decodeOffsetCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// fetch one extra row to know whether there is a next page
+	var dbBlogs []model_db.Blog
+	err = r.getDB(ctx).Scopes(notDeleted, notExpired).
+		Where("user_id IN ?", authorIDs).
+		Where("search @@ plainto_tsquery('simple', ?)", query).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL: "ts_rank(search, plainto_tsquery('simple', ?)) DESC, ts DESC, id DESC", Vars: []interface{}{query}, WithoutParentheses: true,
+		}}).
+		Offset(int(offset)).Limit(page.Limit + 1).
+		Find(&dbBlogs).Error
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(dbBlogs) > page.Limit {
+		dbBlogs = dbBlogs[:page.Limit]
+		nextCursor = (offset + offsetCursor(page.Limit)).encode()
+	}
+
+	blogs := make([]*entity.Blog, len(dbBlogs))
+	for i, dbBlog := range dbBlogs {
+		blogs[i] = r.dbToEntityBlog(dbBlog)
+	}
+
+	return blogs, nextCursor, nil
+}
@@ -2,34 +2,694 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
 
 	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/google/uuid"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
 	"github.com/rifkiadrn/cassandra-explore/internal/entity"
 )
 
+// uuidEpoch is the start of the version 1 UUID clock, 15 Oct 1582
+var uuidEpoch = time.Date(1582, time.October, 15, 0, 0, 0, 0, time.UTC).Unix()
+
+// blogTimeUUID derives the ts key from the blog time, cut to the second like Postgres, and ID.
+// Writing the same blog again overwrites its row.
+func blogTimeUUID(blog entity.Blog) gocql.UUID {
+	ts := (blog.Ts.Unix() - uuidEpoch) * 10000000
+	clock := uint32(blog.ID[8])<<8 | uint32(blog.ID[9])
+
+	return gocql.TimeUUIDWith(ts, clock, blog.ID[10:16])
+}
+
+// blogTTL is the remaining TTL in seconds of an expiring blog, 0 is no TTL
+func blogTTL(blog entity.Blog) int {
+	if blog.ExpiresAt.IsZero() {
+		return 0
+	}
+	return max(int(math.Ceil(time.Until(blog.ExpiresAt).Seconds())), 1)
+}
+
+// blogExpiresAt binds expires_at, unset for a blog that never expires so no tombstone is written
+func blogExpiresAt(blog entity.Blog) interface{} {
+	if blog.ExpiresAt.IsZero() {
+		return gocql.UnsetValue
+	}
+	return blog.ExpiresAt
+}
+
+// expiryHour names the blog_expiries_by_hour partition of an expiry time
+func expiryHour(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15")
+}
+
+// Bucket sizes of the blogs_by_author_bucket partitions
+const (
+	BucketMonth = "month"
+	BucketWeek  = "week"
+)
+
+// blogBucket names the bucket of a blog time by its UTC start date, e.g. 2026-10-01 or the Monday of a week
+func blogBucket(ts time.Time, size string) string {
+	t := ts.UTC()
+	switch size {
+	case BucketWeek:
+		// time.Weekday starts on Sunday, ISO weeks on Monday
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC).Format(time.DateOnly)
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).Format(time.DateOnly)
+	}
+}
+
+// bucketCursor resumes a page inside a bucket
+type bucketCursor struct {
+	Bucket    string `json:"b"`
+	PageState []byte `json:"p,omitempty"`
+}
+
+func (c bucketCursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return encodeCursor(raw)
+}
+
+func decodeBucketCursor(cursor string) (*bucketCursor, error) {
+	raw, err := decodeCursor(cursor)
+	if err != nil || raw == nil {
+		return nil, err
+	}
+
+	var c bucketCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Bucket == "" {
+		return nil, entity.ErrInvalidCursor
+	}
+
+	return &c, nil
+}
+
+// BlogRepositoryNoSQL keeps an author's blogs in one partition per time bucket
 type BlogRepositoryNoSQL struct {
-	db *gocql.Session
+	db     NoSQLSession
+	bucket string
 }
 
-func NewBlogRepositoryNoSQL(db *gocql.Session) BlogRepositoryNoSQL {
+func NewBlogRepositoryNoSQL(db NoSQLSession, bucket string) BlogRepositoryNoSQL {
 	return BlogRepositoryNoSQL{
-		db: db,
+		db:     db,
+		bucket: bucket,
 	}
 }
 
-// Create creates a new blog
+// Create creates a new blog and registers its bucket for the author
 func (r BlogRepositoryNoSQL) Create(ctx context.Context, blogEntity entity.Blog) (*entity.Blog, error) {
 	// Create blog in Cassandra
+	authorId := gocql.UUID(blogEntity.AuthorID)
+	blogId := gocql.UUID(blogEntity.ID)
+	bucket := blogBucket(blogEntity.Ts, r.bucket)
 
-	authorIdStr := blogEntity.AuthorID.String()
+	if err := execNoSQL(ctx, r.db, idempotent, `INSERT INTO blogs_by_author_bucket (author_id, bucket, username, id, content, ts, expires_at, revision) VALUES (?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?`,
+		authorId, bucket, blogEntity.Username, blogId, blogEntity.Content, blogTimeUUID(blogEntity), blogExpiresAt(blogEntity), blogRevision(blogEntity.Revision), blogTTL(blogEntity)); err != nil {
+		return nil, err
+	}
 
-	authorId, _ := gocql.ParseUUID(authorIdStr)
+	if err := execNoSQL(ctx, r.db, idempotent, `INSERT INTO blog_buckets_by_author (author_id, bucket) VALUES (?, ?)`, authorId, bucket); err != nil {
+		return nil, err
+	}
 
-	blogId, _ := gocql.ParseUUID(blogEntity.ID.String())
+	if err := execNoSQL(ctx, r.db, idempotent, `INSERT INTO blogs_by_id (id, author_id, bucket, ts) VALUES (?, ?, ?, ?) USING TTL ?`,
+		blogId, authorId, bucket, blogTimeUUID(blogEntity), blogTTL(blogEntity)); err != nil {
+		return nil, err
+	}
 
-	if err := r.db.Query(`INSERT INTO blogs.blogs_by_author(author_id, username, id, content, ts) VALUES (?, ?, ?, ?, ?)`, authorId, blogEntity.Username, blogId, blogEntity.Content, gocql.UUIDFromTime(blogEntity.Ts)).Exec(); err != nil {
+	if err := r.listExpiry(ctx, blogEntity); err != nil {
 		return nil, err
 	}
 
 	return &blogEntity, nil
 }
+
+// listExpiry lists an expiring blog for the expiry sweeper
+func (r BlogRepositoryNoSQL) listExpiry(ctx context.Context, blog entity.Blog) error {
+	if blog.ExpiresAt.IsZero() || !blog.ExpiresAt.After(time.Now()) {
+		return nil
+	}
+
+	hour := expiryHour(blog.ExpiresAt)
+	if err := execNoSQL(ctx, r.db, idempotent, `INSERT INTO blog_expiries_by_hour (hour, id, author_id, expires_at) VALUES (?, ?, ?, ?)`,
+		hour, gocql.UUID(blog.ID), gocql.UUID(blog.AuthorID), blog.ExpiresAt); err != nil {
+		return err
+	}
+
+	return execNoSQL(ctx, r.db, idempotent, `INSERT INTO blog_expiry_hours (shard, hour) VALUES (0, ?)`, hour)
+}
+
+// Copy writes a blog copied from another store, rewriting the row it already has in place
+func (r BlogRepositoryNoSQL) Copy(ctx context.Context, blog entity.Blog) error {
+	location, err := r.locate(ctx, blog.ID.String())
+	if errors.Is(err, entity.ErrNotFound) {
+		location, err = r.locateInSecond(ctx, blog)
+		if errors.Is(err, entity.ErrNotFound) {
+			_, err := r.Create(ctx, blog)
+			return err
+		}
+		if err == nil {
+			err = execNoSQL(ctx, r.db, idempotent, `INSERT INTO blogs_by_id (id, author_id, bucket, ts) VALUES (?, ?, ?, ?) USING TTL ?`,
+				location.id, location.authorId, location.bucket, location.ts, blogTTL(blog))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := execNoSQL(ctx, r.db, idempotent, `UPDATE blogs_by_author_bucket USING TTL ? SET username = ?, id = ?, content = ?, expires_at = ?, revision = ? WHERE author_id = ? AND bucket = ? AND ts = ?`,
+		blogTTL(blog), blog.Username, location.id, blog.Content, blogExpiresAt(blog), blogRevision(blog.Revision), location.authorId, location.bucket, location.ts); err != nil {
+		return err
+	}
+
+	return r.listExpiry(ctx, blog)
+}
+
+// locateInSecond finds a blog among the author's rows of the second it was written in
+func (r BlogRepositoryNoSQL) locateInSecond(ctx context.Context, blog entity.Blog) (blogLocation, error) {
+	location := blogLocation{
+		id:       gocql.UUID(blog.ID),
+		authorId: gocql.UUID(blog.AuthorID),
+		bucket:   blogBucket(blog.Ts, r.bucket),
+	}
+	second := blog.Ts.Truncate(time.Second)
+
+	iter := queryNoSQL(ctx, r.db, idempotent, `SELECT id, ts FROM blogs_by_author_bucket WHERE author_id = ? AND bucket = ? AND ts >= minTimeuuid(?) AND ts < minTimeuuid(?)`,
+		location.authorId, location.bucket, second, second.Add(time.Second)).IterContext(ctx)
+
+	var (
+		blogId gocql.UUID
+		ts     gocql.UUID
+		found  bool
+	)
+	for iter.Scan(&blogId, &ts) {
+		if blogId == location.id {
+			location.ts = ts
+			found = true
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return blogLocation{}, err
+	}
+	if !found {
+		return blogLocation{}, entity.ErrNotFound
+	}
+
+	return location, nil
+}
+
+// blogLocation is the blogs_by_author_bucket primary key of a blog
+type blogLocation struct {
+	id       gocql.UUID
+	authorId gocql.UUID
+	bucket   string
+	ts       gocql.UUID
+}
+
+// locate reads the row key of a blog from blogs_by_id
+func (r BlogRepositoryNoSQL) locate(ctx context.Context, blogID string) (blogLocation, error) {
+	blogId, err := gocql.ParseUUID(blogID)
+	if err != nil {
+		return blogLocation{}, err
+	}
+
+	location := blogLocation{id: blogId}
+	if err := queryNoSQL(ctx, r.db, idempotent, `SELECT author_id, bucket, ts FROM blogs_by_id WHERE id = ?`, blogId).
+		ScanContext(ctx, &location.authorId, &location.bucket, &location.ts); err != nil {
+		return blogLocation{}, notFound(err)
+	}
+
+	return location, nil
+}
+
+// FindById finds a blog through blogs_by_id
+func (r BlogRepositoryNoSQL) FindById(ctx context.Context, blogID string) (*entity.Blog, error) {
+	location, err := r.locate(ctx, blogID)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := queryNoSQL(ctx, r.db, idempotent, `SELECT author_id, username, id, content, ts, expires_at, revision FROM blogs_by_author_bucket WHERE author_id = ? AND bucket = ? AND ts = ?`,
+		location.authorId, location.bucket, location.ts).IterContext(ctx)
+	blogs := scanBlogs(iter)
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	if len(blogs) == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return blogs[0], nil
+}
+
+// Update sets the content and revision with an LWT on the stored revision
+func (r BlogRepositoryNoSQL) Update(ctx context.Context, blog entity.Blog) (*entity.Blog, error) {
+	location, err := r.locate(ctx, blog.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// turns most conflicts away without an LWT
+	var revision int
+	var content string
+	if err := queryNoSQL(ctx, r.db, idempotent, `SELECT revision, content FROM blogs_by_author_bucket WHERE author_id = ? AND bucket = ? AND ts = ?`,
+		location.authorId, location.bucket, location.ts).ScanContext(ctx, &revision, &content); err != nil {
+		return nil, notFound(err)
+	}
+	if blogRevision(revision) != blog.Revision-1 {
+		return nil, entity.ErrConflict
+	}
+
+	// legacy rows have a null revision
+	var stored interface{}
+	if revision != 0 {
+		stored = revision
+	}
+
+	// LWTs cannot join a multi partition batch, so it runs right away
+	applied, err := queryNoSQL(ctx, r.db, notIdempotent, `UPDATE blogs_by_author_bucket USING TTL ? SET content = ?, revision = ? WHERE author_id = ? AND bucket = ? AND ts = ? IF revision = ?`,
+		blogTTL(blog), blog.Content, blog.Revision, location.authorId, location.bucket, location.ts, stored).MapScanCASContext(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		return nil, entity.ErrConflict
+	}
+
+	if tx := context_db.GetBatch(ctx); tx != nil {
+		tx.Compensate(`UPDATE blogs_by_author_bucket USING TTL ? SET content = ?, revision = ? WHERE author_id = ? AND bucket = ? AND ts = ? IF revision = ?`,
+			blogTTL(blog), content, stored, location.authorId, location.bucket, location.ts, blog.Revision)
+	}
+
+	return &blog, nil
+}
+
+// Delete removes the blog row, its revisions, its expiry entry, then its lookup
+func (r BlogRepositoryNoSQL) Delete(ctx context.Context, blogID string) error {
+	location, err := r.locate(ctx, blogID)
+	if err != nil {
+		return err
+	}
+
+	var expiresAt time.Time
+	if err := queryNoSQL(ctx, r.db, idempotent, `SELECT expires_at FROM blogs_by_author_bucket WHERE author_id = ? AND bucket = ? AND ts = ?`,
+		location.authorId, location.bucket, location.ts).ScanContext(ctx, &expiresAt); err != nil {
+		return notFound(err)
+	}
+	if !expiresAt.IsZero() {
+		if err := execNoSQL(ctx, r.db, idempotent, `DELETE FROM blog_expiries_by_hour WHERE hour = ? AND id = ?`,
+			expiryHour(expiresAt), location.id); err != nil {
+			return err
+		}
+	}
+
+	if err := execNoSQL(ctx, r.db, idempotent, `DELETE FROM blogs_by_author_bucket WHERE author_id = ? AND bucket = ? AND ts = ?`,
+		location.authorId, location.bucket, location.ts); err != nil {
+		return err
+	}
+
+	if err := execNoSQL(ctx, r.db, idempotent, `DELETE FROM blog_revisions WHERE blog_id = ?`, location.id); err != nil {
+		return err
+	}
+
+	return execNoSQL(ctx, r.db, idempotent, `DELETE FROM blogs_by_id WHERE id = ?`, location.id)
+}
+
+// FindAll finds one page of blogs for a user, newest first, walking the author's buckets backwards
+func (r BlogRepositoryNoSQL) FindAll(ctx context.Context, userID string, page entity.Page) ([]*entity.Blog, string, error) {
+	authorId, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cursor, err := decodeBucketCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	from := ""
+	var pageState []byte
+	if cursor != nil {
+		from = cursor.Bucket
+		pageState = cursor.PageState
+	}
+	buckets, err := r.findBuckets(ctx, authorId, from)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var blogs []*entity.Blog
+	for i, bucket := range buckets {
+		// Setting the page state disables auto paging, so the iterator stops after one page
+		iter := queryNoSQL(ctx, r.db, idempotent, `SELECT author_id, username, id, content, ts, expires_at, revision FROM blogs_by_author_bucket WHERE author_id = ? AND bucket = ?`, authorId, bucket).
+			PageSize(page.Limit - len(blogs)).
+			PageState(pageState).
+			IterContext(ctx)
+		nextPageState := iter.PageState()
+
+		blogs = append(blogs, scanBlogs(iter)...)
+		if err := iter.Close(); err != nil {
+			return nil, "", err
+		}
+
+		if len(nextPageState) > 0 {
+			return blogs, bucketCursor{Bucket: bucket, PageState: nextPageState}.encode(), nil
+		}
+		if len(blogs) >= page.Limit {
+			if i+1 < len(buckets) {
+				return blogs, bucketCursor{Bucket: buckets[i+1]}.encode(), nil
+			}
+			return blogs, "", nil
+		}
+		pageState = nil
+	}
+
+	return blogs, "", nil
+}
+
+// FindPage scans one page of the blogs of every author in token order
+func (r BlogRepositoryNoSQL) FindPage(ctx context.Context, page entity.Page) ([]*entity.Blog, string, error) {
+	pageState, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	iter := queryNoSQL(ctx, r.db, idempotent, `SELECT author_id, username, id, content, ts, expires_at, revision FROM blogs_by_author_bucket`).
+		PageSize(page.Limit).
+		PageState(pageState).
+		IterContext(ctx)
+	nextPageState := iter.PageState()
+
+	blogs := scanBlogs(iter)
+	if err := iter.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return blogs, encodeCursor(nextPageState), nil
+}
+
+// FindBefore finds up to limit of an author's blogs older than the position, newest first in feed order
+func (r BlogRepositoryNoSQL) FindBefore(ctx context.Context, userID string, before entity.FeedPosition, limit int) ([]*entity.Blog, error) {
+	authorId, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := ""
+	if !before.IsZero() {
+		from = blogBucket(before.Ts, r.bucket)
+	}
+	buckets, err := r.findBuckets(ctx, authorId, from)
+	if err != nil {
+		return nil, err
+	}
+
+	// a second never spans two buckets, so a full page is complete at the end of a bucket
+	page := feedPage{before: before, limit: limit}
+	for _, bucket := range buckets {
+		query := queryNoSQL(ctx, r.db, idempotent, `SELECT author_id, username, id, content, ts, expires_at, revision FROM blogs_by_author_bucket WHERE author_id = ? AND bucket = ?`,
+			authorId, bucket)
+		if !before.IsZero() {
+			query = queryNoSQL(ctx, r.db, idempotent, `SELECT author_id, username, id, content, ts, expires_at, revision FROM blogs_by_author_bucket WHERE author_id = ? AND bucket = ? AND ts < minTimeuuid(?)`,
+				authorId, bucket, nextSecond(before))
+		}
+		iter := query.PageSize(limit + 1).IterContext(ctx)
+
+		scanEachBlog(iter, page.add)
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+
+		if page.full() {
+			break
+		}
+	}
+
+	return page.result(), nil
+}
+
+// DeleteExpired takes up to limit expired blogs off the expiry list and returns them
+func (r BlogRepositoryNoSQL) DeleteExpired(ctx context.Context, now time.Time, limit int) ([]*entity.Blog, error) {
+	hours, err := r.expiryHours(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// a blog created at the end of an hour can be listed in it late
+	settled := expiryHour(now.Add(-time.Hour))
+
+	var blogs []*entity.Blog
+	for _, hour := range hours {
+		due, pending, err := r.dueExpiries(ctx, hour, now)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, blog := range due {
+			if len(blogs) == limit {
+				return blogs, nil
+			}
+
+			claimed, err := r.claimExpiry(ctx, hour, blog)
+			if err != nil {
+				return nil, err
+			}
+			if claimed {
+				blogs = append(blogs, blog)
+			}
+		}
+
+		if !pending && hour < settled {
+			if err := execNoSQL(ctx, r.db, idempotent, `DELETE FROM blog_expiry_hours WHERE shard = 0 AND hour = ?`, hour); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return blogs, nil
+}
+
+// expiryHours lists the expiry hours up to the one of now
+func (r BlogRepositoryNoSQL) expiryHours(ctx context.Context, now time.Time) ([]string, error) {
+	iter := queryNoSQL(ctx, r.db, idempotent, `SELECT hour FROM blog_expiry_hours WHERE shard = 0 AND hour <= ?`, expiryHour(now)).IterContext(ctx)
+
+	var (
+		hours []string
+		hour  string
+	)
+	for iter.Scan(&hour) {
+		hours = append(hours, hour)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return hours, nil
+}
+
+// dueExpiries reads the blogs of an expiry hour expired by now
+func (r BlogRepositoryNoSQL) dueExpiries(ctx context.Context, hour string, now time.Time) ([]*entity.Blog, bool, error) {
+	iter := queryNoSQL(ctx, r.db, idempotent, `SELECT id, author_id, expires_at FROM blog_expiries_by_hour WHERE hour = ?`, hour).IterContext(ctx)
+
+	var (
+		due       []*entity.Blog
+		pending   bool
+		blogId    gocql.UUID
+		authorId  gocql.UUID
+		expiresAt time.Time
+	)
+	for iter.Scan(&blogId, &authorId, &expiresAt) {
+		if expiresAt.After(now) {
+			pending = true
+			continue
+		}
+		due = append(due, &entity.Blog{ID: uuid.UUID(blogId), AuthorID: uuid.UUID(authorId), ExpiresAt: expiresAt})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, false, err
+	}
+
+	return due, pending, nil
+}
+
+// claimExpiry removes a blog from the expiry list with an LWT
+func (r BlogRepositoryNoSQL) claimExpiry(ctx context.Context, hour string, blog *entity.Blog) (bool, error) {
+	applied, err := queryNoSQL(ctx, r.db, notIdempotent, `DELETE FROM blog_expiries_by_hour WHERE hour = ? AND id = ? IF EXISTS`,
+		hour, gocql.UUID(blog.ID)).MapScanCASContext(ctx, map[string]interface{}{})
+	if err != nil || !applied {
+		return false, err
+	}
+
+	if tx := context_db.GetBatch(ctx); tx != nil {
+		tx.Compensate(`INSERT INTO blog_expiries_by_hour (hour, id, author_id, expires_at) VALUES (?, ?, ?, ?) IF NOT EXISTS`,
+			hour, gocql.UUID(blog.ID), gocql.UUID(blog.AuthorID), blog.ExpiresAt)
+	}
+
+	return true, nil
+}
+
+// CountByAuthor counts an author's blogs, expired ones included until they are swept
+func (r BlogRepositoryNoSQL) CountByAuthor(ctx context.Context, authorID string) (int64, error) {
+	authorId, err := gocql.ParseUUID(authorID)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	total, err := r.countUnswept(ctx, uuid.UUID(authorId), now)
+	if err != nil {
+		return 0, err
+	}
+
+	buckets, err := r.findBuckets(ctx, authorId, "")
+	if err != nil {
+		return 0, err
+	}
+
+	for _, bucket := range buckets {
+		var count int64
+		if err := queryNoSQL(ctx, r.db, idempotent, `SELECT COUNT(*) FROM blogs_by_author_bucket WHERE author_id = ? AND bucket = ?`, authorId, bucket).
+			ScanContext(ctx, &count); err != nil {
+			return 0, err
+		}
+		total += count
+	}
+
+	return total, nil
+}
+
+// countUnswept counts an author's blogs expired by now that are still on the expiry list
+func (r BlogRepositoryNoSQL) countUnswept(ctx context.Context, authorID uuid.UUID, now time.Time) (int64, error) {
+	hours, err := r.expiryHours(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, hour := range hours {
+		due, _, err := r.dueExpiries(ctx, hour, now)
+		if err != nil {
+			return 0, err
+		}
+		for _, blog := range due {
+			if blog.AuthorID == authorID {
+				count++
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// findBuckets lists an author's buckets newest first, starting at from when it is set
+func (r BlogRepositoryNoSQL) findBuckets(ctx context.Context, authorId gocql.UUID, from string) ([]string, error) {
+	query := queryNoSQL(ctx, r.db, idempotent, `SELECT bucket FROM blog_buckets_by_author WHERE author_id = ?`, authorId)
+	if from != "" {
+		query = queryNoSQL(ctx, r.db, idempotent, `SELECT bucket FROM blog_buckets_by_author WHERE author_id = ? AND bucket <= ?`, authorId, from)
+	}
+	iter := query.IterContext(ctx)
+
+	var (
+		buckets []string
+		bucket  string
+	)
+	for iter.Scan(&bucket) {
+		buckets = append(buckets, bucket)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("find buckets: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// blogRevision reads a revision, a legacy row without one is the first
+func blogRevision(revision int) int {
+	return max(revision, 1)
+}
+
+// feedPage keeps one feed page from a newest first scan, sorted once complete
+type feedPage struct {
+	before entity.FeedPosition
+	limit  int
+	blogs  []*entity.Blog
+}
+
+// add keeps a scanned blog older than the position, false once the page is complete
+func (p *feedPage) add(blog *entity.Blog) bool {
+	if p.full() && blog.Ts.Unix() != p.blogs[len(p.blogs)-1].Ts.Unix() {
+		return false
+	}
+	if p.before.IsZero() || olderThan(*blog, p.before) {
+		p.blogs = append(p.blogs, blog)
+	}
+	return true
+}
+
+func (p *feedPage) full() bool {
+	return len(p.blogs) >= p.limit
+}
+
+// result is the first limit blogs kept in feed order
+func (p *feedPage) result() []*entity.Blog {
+	sortNewestFirst(p.blogs)
+	if len(p.blogs) > p.limit {
+		return p.blogs[:p.limit]
+	}
+	return p.blogs
+}
+
+// nextSecond is the start of the second after the position
+func nextSecond(position entity.FeedPosition) time.Time {
+	return time.Unix(position.Ts.Unix()+1, 0)
+}
+
+// scanBlogs reads author_id, username, id, content, ts, expires_at, revision rows
+func scanBlogs(iter *gocql.Iter) []*entity.Blog {
+	var blogs []*entity.Blog
+	scanEachBlog(iter, func(blog *entity.Blog) bool {
+		blogs = append(blogs, blog)
+		return true
+	})
+
+	return blogs
+}
+
+// scanEachBlog hands the blogs of rows like scanBlogs to fn until it returns false
+func scanEachBlog(iter *gocql.Iter, fn func(blog *entity.Blog) bool) {
+	var (
+		rowAuthor gocql.UUID
+		username  string
+		blogId    gocql.UUID
+		content   string
+		ts        gocql.UUID
+		expiresAt time.Time
+		revision  int
+	)
+	for iter.Scan(&rowAuthor, &username, &blogId, &content, &ts, &expiresAt, &revision) {
+		// only the content of a deleted blog, see Update
+		if blogId == (gocql.UUID{}) {
+			continue
+		}
+		blog := &entity.Blog{
+			ID:        uuid.UUID(blogId),
+			AuthorID:  uuid.UUID(rowAuthor),
+			Username:  username,
+			Content:   content,
+			Ts:        ts.Time(),
+			ExpiresAt: expiresAt,
+			Revision:  blogRevision(revision),
+		}
+		if !fn(blog) {
+			return
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	model_db "github.com/rifkiadrn/cassandra-explore/internal/model/db"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// BlogCountRepository keeps the blog_count column of the Postgres users table
+type BlogCountRepository struct {
+	db  *gorm.DB
+	log *logrus.Logger
+}
+
+func NewBlogCountRepository(db *gorm.DB, log *logrus.Logger) BlogCountRepository {
+	return BlogCountRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *BlogCountRepository) getDB(ctx context.Context) *gorm.DB {
+	if tx := context_db.GetTx(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+// Increment adds delta to the author's count, inside the transaction of ctx if there is one
+func (r BlogCountRepository) Increment(ctx context.Context, authorID string, delta int64) error {
+	return r.getDB(ctx).Model(&model_db.User{}).Where("id = ?", authorID).
+		UpdateColumn("blog_count", gorm.Expr("blog_count + ?", delta)).Error
+}
+
+// Count reads the author's count, 0 for an unknown author
+func (r BlogCountRepository) Count(ctx context.Context, authorID string) (int64, error) {
+	var counts []int64
+	if err := r.getDB(ctx).Model(&model_db.User{}).Where("id = ?", authorID).Pluck("blog_count", &counts).Error; err != nil {
+		return 0, err
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+	return counts[0], nil
+}
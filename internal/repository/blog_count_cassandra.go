@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
+)
+
+// BlogCountRepositoryNoSQL keeps the blog_counts_by_author counters
+type BlogCountRepositoryNoSQL struct {
+	db  NoSQLSession
+	log *logrus.Logger
+}
+
+func NewBlogCountRepositoryNoSQL(db NoSQLSession, log *logrus.Logger) BlogCountRepositoryNoSQL {
+	return BlogCountRepositoryNoSQL{
+		db:  db,
+		log: log,
+	}
+}
+
+// Increment adds delta to the author's counter once the unit of work of ctx commits
+func (r BlogCountRepositoryNoSQL) Increment(ctx context.Context, authorID string, delta int64) error {
+	authorId, err := gocql.ParseUUID(authorID)
+	if err != nil {
+		return err
+	}
+
+	increment := func() error {
+		return queryNoSQL(ctx, r.db, notIdempotent, `UPDATE blog_counts_by_author SET blogs = blogs + ? WHERE author_id = ?`, delta, authorId).
+			ExecContext(ctx)
+	}
+	if !context_db.InTransaction(ctx) {
+		return increment()
+	}
+
+	context_db.AfterCommit(ctx, func() {
+		if err := increment(); err != nil {
+			r.log.Warnf("Failed to count blog of %s : %+v", authorID, err)
+		}
+	})
+	return nil
+}
+
+// Count reads the author's counter, an author without blogs has no row
+func (r BlogCountRepositoryNoSQL) Count(ctx context.Context, authorID string) (int64, error) {
+	authorId, err := gocql.ParseUUID(authorID)
+	if err != nil {
+		return 0, err
+	}
+
+	var blogs int64
+	err = queryNoSQL(ctx, r.db, idempotent, `SELECT blogs FROM blog_counts_by_author WHERE author_id = ?`, authorId).ScanContext(ctx, &blogs)
+	if errors.Is(notFound(err), entity.ErrNotFound) {
+		return 0, nil
+	}
+	return blogs, err
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+// BlogCountRepositoryDual counts blogs in both stores, like BlogRepositoryDual
+type BlogCountRepositoryDual struct {
+	primary   usecase.IBlogCountRepo
+	secondary usecase.IBlogCountRepo
+	log       *logrus.Logger
+}
+
+func NewBlogCountRepositoryDual(primary usecase.IBlogCountRepo, secondary usecase.IBlogCountRepo, log *logrus.Logger) BlogCountRepositoryDual {
+	return BlogCountRepositoryDual{
+		primary:   primary,
+		secondary: secondary,
+		log:       log,
+	}
+}
+
+// Increment updates the primary count, then the secondary one
+func (r BlogCountRepositoryDual) Increment(ctx context.Context, authorID string, delta int64) error {
+	if err := r.primary.Increment(ctx, authorID, delta); err != nil {
+		return err
+	}
+
+	context_db.AfterCommit(ctx, func() {
+		if err := r.secondary.Increment(ctx, authorID, delta); err != nil {
+			r.log.Warnf("Failed to count blog of %s in secondary store : %+v", authorID, err)
+		}
+	})
+
+	return nil
+}
+
+// Count reads the primary count
+func (r BlogCountRepositoryDual) Count(ctx context.Context, authorID string) (int64, error) {
+	return r.primary.Count(ctx, authorID)
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+)
+
+// BlogCountRepositoryMemory keeps the blog count of each author in process
+type BlogCountRepositoryMemory struct {
+	store  *context_db.MemoryStore
+	counts map[string]int64
+}
+
+func NewBlogCountRepositoryMemory(store *context_db.MemoryStore) BlogCountRepositoryMemory {
+	return BlogCountRepositoryMemory{
+		store:  store,
+		counts: map[string]int64{},
+	}
+}
+
+// Increment adds delta to the author's count, inside the transaction of ctx if there is one
+func (r BlogCountRepositoryMemory) Increment(ctx context.Context, authorID string, delta int64) error {
+	authorID = strings.Clone(authorID)
+
+	return r.store.Write(ctx, func(undo func(func())) error {
+		r.counts[authorID] += delta
+		undo(func() {
+			r.counts[authorID] -= delta
+		})
+		return nil
+	})
+}
+
+// Count reads the author's count, 0 for an unknown author
+func (r BlogCountRepositoryMemory) Count(ctx context.Context, authorID string) (int64, error) {
+	var count int64
+	r.store.Read(ctx, func() {
+		count = r.counts[authorID]
+	})
+	return count, nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+// BlogRepositoryDual writes blogs to both stores and reads from the primary one.
+// Secondary writes are best effort and run after the primary commit.
+type BlogRepositoryDual struct {
+	primary   usecase.IBlog
+	secondary usecase.IBlog
+	log       *logrus.Logger
+}
+
+func NewBlogRepositoryDual(primary usecase.IBlog, secondary usecase.IBlog, log *logrus.Logger) BlogRepositoryDual {
+	return BlogRepositoryDual{
+		primary:   primary,
+		secondary: secondary,
+		log:       log,
+	}
+}
+
+// Create creates the blog in the primary store, then in the secondary store
+func (r BlogRepositoryDual) Create(ctx context.Context, blog entity.Blog) (*entity.Blog, error) {
+	created, err := r.primary.Create(ctx, blog)
+	if err != nil {
+		return nil, err
+	}
+
+	context_db.AfterCommit(ctx, func() {
+		if _, err := r.secondary.Create(ctx, blog); err != nil {
+			r.log.Warnf("Failed to copy blog %s to secondary store : %+v", blog.ID, err)
+		}
+	})
+
+	return created, nil
+}
+
+// FindAll finds one page of blogs for a user from the primary store
+func (r BlogRepositoryDual) FindAll(ctx context.Context, userID string, page entity.Page) ([]*entity.Blog, string, error) {
+	return r.primary.FindAll(ctx, userID, page)
+}
+
+// FindBefore reads from the primary store like FindAll
+func (r BlogRepositoryDual) FindBefore(ctx context.Context, userID string, before entity.FeedPosition, limit int) ([]*entity.Blog, error) {
+	return r.primary.FindBefore(ctx, userID, before, limit)
+}
+
+// FindById reads from the primary store like FindAll
+func (r BlogRepositoryDual) FindById(ctx context.Context, blogID string) (*entity.Blog, error) {
+	return r.primary.FindById(ctx, blogID)
+}
+
+// Update updates the blog in the primary store, then in the secondary store
+func (r BlogRepositoryDual) Update(ctx context.Context, blog entity.Blog) (*entity.Blog, error) {
+	updated, err := r.primary.Update(ctx, blog)
+	if err != nil {
+		return nil, err
+	}
+
+	context_db.AfterCommit(ctx, func() {
+		if _, err := r.secondary.Update(ctx, blog); err != nil {
+			r.log.Warnf("Failed to update blog %s in secondary store : %+v", blog.ID, err)
+		}
+	})
+
+	return updated, nil
+}
+
+// Delete deletes the blog from the primary store, then from the secondary store
+func (r BlogRepositoryDual) Delete(ctx context.Context, blogID string) error {
+	if err := r.primary.Delete(ctx, blogID); err != nil {
+		return err
+	}
+
+	// a blog left in the secondary store shows up in cmd/reconcile as missing in the primary one
+	context_db.AfterCommit(ctx, func() {
+		if err := r.secondary.Delete(ctx, blogID); err != nil {
+			r.log.Warnf("Failed to delete blog %s from secondary store : %+v", blogID, err)
+		}
+	})
+
+	return nil
+}
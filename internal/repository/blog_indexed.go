@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+// BlogRepositoryIndexed keeps a search index of the blogs written to a store that cannot search
+type BlogRepositoryIndexed struct {
+	usecase.IBlog
+	index usecase.ISearchIndex
+	log   *logrus.Logger
+}
+
+func NewBlogRepositoryIndexed(blogs usecase.IBlog, index usecase.ISearchIndex, log *logrus.Logger) BlogRepositoryIndexed {
+	return BlogRepositoryIndexed{
+		IBlog: blogs,
+		index: index,
+		log:   log,
+	}
+}
+
+// Create creates the blog in the store, then indexes it
+func (r BlogRepositoryIndexed) Create(ctx context.Context, blog entity.Blog) (*entity.Blog, error) {
+	created, err := r.IBlog.Create(ctx, blog)
+	if err != nil {
+		return nil, err
+	}
+
+	// the blog is stored, a failure here only keeps it out of search results
+	if err := r.index.Index(ctx, *created); err != nil {
+		r.log.Warnf("Failed to index blog %s : %+v", created.ID, err)
+	}
+
+	return created, nil
+}
+
+// Update updates the blog in the store, then reindexes it
+func (r BlogRepositoryIndexed) Update(ctx context.Context, blog entity.Blog) (*entity.Blog, error) {
+	updated, err := r.IBlog.Update(ctx, blog)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.index.Index(ctx, *updated); err != nil {
+		r.log.Warnf("Failed to index blog %s : %+v", updated.ID, err)
+	}
+
+	return updated, nil
+}
+
+// Delete deletes the blog from the store, then from the index
+func (r BlogRepositoryIndexed) Delete(ctx context.Context, blogID string) error {
+	if err := r.IBlog.Delete(ctx, blogID); err != nil {
+		return err
+	}
+
+	if err := r.index.Remove(ctx, blogID); err != nil {
+		r.log.Warnf("Failed to unindex blog %s : %+v", blogID, err)
+	}
+
+	return nil
+}
+
+// Search reads one page of hits from the index and the blogs from the store, dropping hits it no longer finds
+func (r BlogRepositoryIndexed) Search(ctx context.Context, query string, authorIDs []uuid.UUID, page entity.Page) ([]*entity.Blog, string, error) {
+	offset, err := decodeOffsetCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// fetch one extra hit to know whether there is a next page
+	ids, err := r.index.Search(ctx, query, authorIDs, int(offset), page.Limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	more := len(ids) > page.Limit
+	if more {
+		ids = ids[:page.Limit]
+	}
+
+	removed := 0
+	blogs := make([]*entity.Blog, 0, len(ids))
+	for _, id := range ids {
+		blog, err := r.IBlog.FindById(ctx, id.String())
+		if errors.Is(err, entity.ErrNotFound) {
+			if err := r.index.Remove(ctx, id.String()); err != nil {
+				r.log.Warnf("Failed to unindex blog %s : %+v", id, err)
+			}
+			removed++
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		blogs = append(blogs, blog)
+	}
+
+	// the hits removed above no longer count towards the offset of the next page
+	nextCursor := ""
+	if more {
+		nextCursor = (offset + offsetCursor(page.Limit-removed)).encode()
+	}
+
+	return blogs, nextCursor, nil
+}
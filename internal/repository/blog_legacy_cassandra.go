@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/google/uuid"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// BlogLegacyRepositoryNoSQL reads the unbucketed blogs_by_author table
+type BlogLegacyRepositoryNoSQL struct {
+	db NoSQLSession
+}
+
+func NewBlogLegacyRepositoryNoSQL(db NoSQLSession) BlogLegacyRepositoryNoSQL {
+	return BlogLegacyRepositoryNoSQL{
+		db: db,
+	}
+}
+
+// FindPage scans one page of the whole table in token order, resuming from the driver paging state
+func (r BlogLegacyRepositoryNoSQL) FindPage(ctx context.Context, page entity.Page) ([]*entity.Blog, string, error) {
+	pageState, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	iter := queryNoSQL(ctx, r.db, idempotent, `SELECT author_id, username, id, content, ts FROM blogs_by_author`).
+		PageSize(page.Limit).
+		PageState(pageState).
+		IterContext(ctx)
+	nextPageState := iter.PageState()
+
+	var (
+		blogs     []*entity.Blog
+		rowAuthor gocql.UUID
+		username  string
+		blogId    gocql.UUID
+		content   string
+		ts        gocql.UUID
+	)
+	for iter.Scan(&rowAuthor, &username, &blogId, &content, &ts) {
+		blogs = append(blogs, &entity.Blog{
+			ID:       uuid.UUID(blogId),
+			AuthorID: uuid.UUID(rowAuthor),
+			Username: username,
+			Content:  content,
+			Ts:       ts.Time(),
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return blogs, encodeCursor(nextPageState), nil
+}
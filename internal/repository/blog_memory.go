@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// BlogRepositoryMemory keeps blogs in process, like the Postgres repository
+type BlogRepositoryMemory struct {
+	store *context_db.MemoryStore
+	blogs map[string]entity.Blog
+}
+
+func NewBlogRepositoryMemory(store *context_db.MemoryStore) BlogRepositoryMemory {
+	return BlogRepositoryMemory{
+		store: store,
+		blogs: map[string]entity.Blog{},
+	}
+}
+
+// Create creates a new blog, a taken ID is entity.ErrConflict
+func (r BlogRepositoryMemory) Create(ctx context.Context, blog entity.Blog) (*entity.Blog, error) {
+	if blog.ID == uuid.Nil {
+		blog.ID = uuid.New()
+	}
+	if blog.Ts.IsZero() {
+		blog.Ts = time.Now()
+	}
+	blog.Revision = blogRevision(blog.Revision)
+
+	err := r.store.Write(ctx, func(undo func(func())) error {
+		id := blog.ID.String()
+		if _, ok := r.blogs[id]; ok {
+			return entity.ErrConflict
+		}
+
+		r.blogs[id] = blog
+		undo(func() {
+			delete(r.blogs, id)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &blog, nil
+}
+
+// FindAll finds one page of blogs for a user, newest first, using a (ts, id) keyset
+func (r BlogRepositoryMemory) FindAll(ctx context.Context, userID string, page entity.Page) ([]*entity.Blog, string, error) {
+	before, err := entity.DecodeFeedPosition(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// fetch one extra blog to know whether there is a next page
+	blogs, err := r.FindBefore(ctx, userID, before, page.Limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(blogs) > page.Limit {
+		blogs = blogs[:page.Limit]
+		last := blogs[len(blogs)-1]
+		nextCursor = entity.FeedPositionOf(*last).Encode()
+	}
+
+	return blogs, nextCursor, nil
+}
+
+// FindBefore finds up to limit of a user's blogs older than the position, newest first
+func (r BlogRepositoryMemory) FindBefore(ctx context.Context, userID string, before entity.FeedPosition, limit int) ([]*entity.Blog, error) {
+	now := time.Now()
+
+	var blogs []*entity.Blog
+	r.store.Read(ctx, func() {
+		for _, blog := range r.blogs {
+			if blog.AuthorID.String() != userID || blogExpired(blog, now) {
+				continue
+			}
+			if !before.IsZero() && !olderThan(blog, before) {
+				continue
+			}
+			blog := blog
+			blogs = append(blogs, &blog)
+		}
+	})
+
+	sortNewestFirst(blogs)
+	if len(blogs) > limit {
+		blogs = blogs[:limit]
+	}
+
+	return blogs, nil
+}
+
+// FindById finds a blog that is not expired
+func (r BlogRepositoryMemory) FindById(ctx context.Context, blogID string) (*entity.Blog, error) {
+	var (
+		blog entity.Blog
+		ok   bool
+	)
+	r.store.Read(ctx, func() {
+		blog, ok = r.blogs[blogID]
+	})
+	if !ok || blogExpired(blog, time.Now()) {
+		return nil, entity.ErrNotFound
+	}
+
+	return &blog, nil
+}
+
+// Update rewrites the content of a blog, an edit not on top of the previous revision is entity.ErrConflict
+func (r BlogRepositoryMemory) Update(ctx context.Context, blog entity.Blog) (*entity.Blog, error) {
+	var updated entity.Blog
+	err := r.store.Write(ctx, func(undo func(func())) error {
+		id := blog.ID.String()
+		stored, ok := r.blogs[id]
+		if !ok {
+			return entity.ErrNotFound
+		}
+		if stored.Revision != blog.Revision-1 {
+			return entity.ErrConflict
+		}
+
+		updated = stored
+		updated.Content = blog.Content
+		updated.Revision = blog.Revision
+		r.blogs[id] = updated
+		undo(func() {
+			r.blogs[id] = stored
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// Delete deletes a blog
+func (r BlogRepositoryMemory) Delete(ctx context.Context, blogID string) error {
+	return r.store.Write(ctx, func(undo func(func())) error {
+		stored, ok := r.blogs[blogID]
+		if !ok {
+			return entity.ErrNotFound
+		}
+
+		delete(r.blogs, blogID)
+		undo(func() {
+			r.blogs[blogID] = stored
+		})
+		return nil
+	})
+}
+
+// FindBatch finds up to limit unexpired blogs with an ID greater than afterID, in ID order
+func (r BlogRepositoryMemory) FindBatch(ctx context.Context, afterID string, limit int) ([]*entity.Blog, error) {
+	now := time.Now()
+
+	var blogs []*entity.Blog
+	r.store.Read(ctx, func() {
+		for id, blog := range r.blogs {
+			if id > afterID && !blogExpired(blog, now) {
+				blog := blog
+				blogs = append(blogs, &blog)
+			}
+		}
+	})
+
+	sort.Slice(blogs, func(i, j int) bool {
+		return blogs[i].ID.String() < blogs[j].ID.String()
+	})
+	if len(blogs) > limit {
+		blogs = blogs[:limit]
+	}
+
+	return blogs, nil
+}
+
+// DeleteExpired deletes up to limit blogs that expired at or before now and returns them
+func (r BlogRepositoryMemory) DeleteExpired(ctx context.Context, now time.Time, limit int) ([]*entity.Blog, error) {
+	var blogs []*entity.Blog
+	err := r.store.Write(ctx, func(undo func(func())) error {
+		for id, blog := range r.blogs {
+			if len(blogs) == limit {
+				break
+			}
+			if blog.ExpiresAt.IsZero() || blog.ExpiresAt.After(now) {
+				continue
+			}
+
+			id, blog := id, blog
+			delete(r.blogs, id)
+			undo(func() {
+				r.blogs[id] = blog
+			})
+			blogs = append(blogs, &blog)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blogs, nil
+}
+
+// CountByAuthor counts the blogs of an author, expired ones included until they are swept
+func (r BlogRepositoryMemory) CountByAuthor(ctx context.Context, authorID string) (int64, error) {
+	var count int64
+	r.store.Read(ctx, func() {
+		for _, blog := range r.blogs {
+			if blog.AuthorID.String() == authorID {
+				count++
+			}
+		}
+	})
+	return count, nil
+}
+
+// blogExpired tells whether a blog with an expiry is past it
+func blogExpired(blog entity.Blog, now time.Time) bool {
+	return !blog.ExpiresAt.IsZero() && !blog.ExpiresAt.After(now)
+}
+
+// olderThan tells whether a blog comes strictly after the feed position
+func olderThan(blog entity.Blog, position entity.FeedPosition) bool {
+	return position.Before(entity.FeedPositionOf(blog))
+}
+
+// sortNewestFirst orders blogs in feed order, the order of the Postgres keyset
+func sortNewestFirst(blogs []*entity.Blog) {
+	sort.Slice(blogs, func(i, j int) bool {
+		return entity.FeedPositionOf(*blogs[i]).Before(entity.FeedPositionOf(*blogs[j]))
+	})
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	model_db "github.com/rifkiadrn/cassandra-explore/internal/model/db"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// BlogRevisionRepository keeps the revisions of blogs in Postgres, one row per blog and revision
+type BlogRevisionRepository struct {
+	db  *gorm.DB
+	log *logrus.Logger
+}
+
+func NewBlogRevisionRepository(db *gorm.DB, log *logrus.Logger) BlogRevisionRepository {
+	return BlogRevisionRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *BlogRevisionRepository) getDB(ctx context.Context) *gorm.DB {
+	if tx := context_db.GetTx(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+// entityToDBBlogRevision converts domain entity to DB model
+func (r BlogRevisionRepository) entityToDBBlogRevision(e entity.BlogRevision) model_db.BlogRevision {
+	dbRevision := model_db.BlogRevision{
+		BlogID:   e.BlogID,
+		Revision: e.Revision,
+		Content:  e.Content,
+		EditedAt: e.EditedAt.Unix(),
+	}
+	if !e.ExpiresAt.IsZero() {
+		expiresAt := e.ExpiresAt.Unix()
+		dbRevision.ExpiresAt = &expiresAt
+	}
+	return dbRevision
+}
+
+// dbToEntityBlogRevision converts DB model to domain entity pointer
+func (r BlogRevisionRepository) dbToEntityBlogRevision(db model_db.BlogRevision) *entity.BlogRevision {
+	revision := &entity.BlogRevision{
+		BlogID:   db.BlogID,
+		Revision: db.Revision,
+		Content:  db.Content,
+		EditedAt: time.Unix(db.EditedAt, 0),
+	}
+	if db.ExpiresAt != nil {
+		revision.ExpiresAt = time.Unix(*db.ExpiresAt, 0)
+	}
+	return revision
+}
+
+// Create saves a revision, a second save of the same revision is entity.ErrConflict
+func (r BlogRevisionRepository) Create(ctx context.Context, revision entity.BlogRevision) error {
+	dbRevision := r.entityToDBBlogRevision(revision)
+	return conflict(r.getDB(ctx).Create(&dbRevision).Error)
+}
+
+// FindAll finds every revision of a blog, newest first
+func (r BlogRevisionRepository) FindAll(ctx context.Context, blogID string) ([]*entity.BlogRevision, error) {
+	var dbRevisions []model_db.BlogRevision
+	if err := r.getDB(ctx).Where("blog_id = ?", blogID).Order("revision DESC").Find(&dbRevisions).Error; err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*entity.BlogRevision, len(dbRevisions))
+	for i, dbRevision := range dbRevisions {
+		revisions[i] = r.dbToEntityBlogRevision(dbRevision)
+	}
+
+	return revisions, nil
+}
+
+// FindByRevision finds one revision of a blog
+func (r BlogRevisionRepository) FindByRevision(ctx context.Context, blogID string, revision int) (*entity.BlogRevision, error) {
+	var dbRevision model_db.BlogRevision
+	if err := r.getDB(ctx).Where("blog_id = ? AND revision = ?", blogID, revision).First(&dbRevision).Error; err != nil {
+		return nil, notFound(err)
+	}
+
+	return r.dbToEntityBlogRevision(dbRevision), nil
+}
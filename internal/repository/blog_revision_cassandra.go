@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/google/uuid"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// BlogRevisionRepositoryNoSQL keeps the revisions of a blog in one blog_revisions partition
+type BlogRevisionRepositoryNoSQL struct {
+	db NoSQLSession
+}
+
+func NewBlogRevisionRepositoryNoSQL(db NoSQLSession) BlogRevisionRepositoryNoSQL {
+	return BlogRevisionRepositoryNoSQL{
+		db: db,
+	}
+}
+
+// Create saves a revision, expiring with its blog
+func (r BlogRevisionRepositoryNoSQL) Create(ctx context.Context, revision entity.BlogRevision) error {
+	blog := entity.Blog{ExpiresAt: revision.ExpiresAt}
+	return execNoSQL(ctx, r.db, idempotent, `INSERT INTO blog_revisions (blog_id, edited_at, revision, content, expires_at) VALUES (?, ?, ?, ?, ?) USING TTL ?`,
+		gocql.UUID(revision.BlogID), revisionTimeUUID(revision), revision.Revision, revision.Content, blogExpiresAt(blog), blogTTL(blog))
+}
+
+// FindAll finds every revision of a blog, newest first
+func (r BlogRevisionRepositoryNoSQL) FindAll(ctx context.Context, blogID string) ([]*entity.BlogRevision, error) {
+	blogId, err := gocql.ParseUUID(blogID)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := queryNoSQL(ctx, r.db, idempotent, `SELECT blog_id, edited_at, revision, content, expires_at FROM blog_revisions WHERE blog_id = ?`, blogId).
+		IterContext(ctx)
+
+	var (
+		revisions []*entity.BlogRevision
+		rowBlog   gocql.UUID
+		editedAt  gocql.UUID
+		revision  int
+		content   string
+		expiresAt time.Time
+	)
+	for iter.Scan(&rowBlog, &editedAt, &revision, &content, &expiresAt) {
+		revisions = append(revisions, &entity.BlogRevision{
+			BlogID:    uuid.UUID(rowBlog),
+			Revision:  revision,
+			Content:   content,
+			EditedAt:  editedAt.Time(),
+			ExpiresAt: expiresAt,
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// FindByRevision finds one revision of a blog in its partition, the newest row when edits raced
+func (r BlogRevisionRepositoryNoSQL) FindByRevision(ctx context.Context, blogID string, revision int) (*entity.BlogRevision, error) {
+	revisions, err := r.FindAll(ctx, blogID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, found := range revisions {
+		if found.Revision == revision {
+			return found, nil
+		}
+	}
+
+	return nil, entity.ErrNotFound
+}
+
+// revisionTimeUUID derives the edited_at key from the edit time and revision
+func revisionTimeUUID(revision entity.BlogRevision) gocql.UUID {
+	id := revision.BlogID
+	id[8], id[9] = byte(revision.Revision>>8), byte(revision.Revision)
+	return blogTimeUUID(entity.Blog{ID: id, Ts: revision.EditedAt})
+}
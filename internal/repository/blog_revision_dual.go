@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+// BlogRevisionRepositoryDual saves revisions to both stores, like BlogRepositoryDual
+type BlogRevisionRepositoryDual struct {
+	primary   usecase.IBlogRevisionRepo
+	secondary usecase.IBlogRevisionRepo
+	log       *logrus.Logger
+}
+
+func NewBlogRevisionRepositoryDual(primary usecase.IBlogRevisionRepo, secondary usecase.IBlogRevisionRepo, log *logrus.Logger) BlogRevisionRepositoryDual {
+	return BlogRevisionRepositoryDual{
+		primary:   primary,
+		secondary: secondary,
+		log:       log,
+	}
+}
+
+// Create saves the revision in the primary store, then in the secondary store
+func (r BlogRevisionRepositoryDual) Create(ctx context.Context, revision entity.BlogRevision) error {
+	if err := r.primary.Create(ctx, revision); err != nil {
+		return err
+	}
+
+	context_db.AfterCommit(ctx, func() {
+		if err := r.secondary.Create(ctx, revision); err != nil {
+			r.log.Warnf("Failed to copy revision %d of blog %s to secondary store : %+v", revision.Revision, revision.BlogID, err)
+		}
+	})
+
+	return nil
+}
+
+// FindAll reads from the primary store
+func (r BlogRevisionRepositoryDual) FindAll(ctx context.Context, blogID string) ([]*entity.BlogRevision, error) {
+	return r.primary.FindAll(ctx, blogID)
+}
+
+// FindByRevision reads from the primary store
+func (r BlogRevisionRepositoryDual) FindByRevision(ctx context.Context, blogID string, revision int) (*entity.BlogRevision, error) {
+	return r.primary.FindByRevision(ctx, blogID, revision)
+}
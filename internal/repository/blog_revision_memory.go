@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// BlogRevisionRepositoryMemory keeps the revisions of blogs in process, keyed by blog and revision
+type BlogRevisionRepositoryMemory struct {
+	store     *context_db.MemoryStore
+	revisions map[string]map[int]entity.BlogRevision
+}
+
+func NewBlogRevisionRepositoryMemory(store *context_db.MemoryStore) BlogRevisionRepositoryMemory {
+	return BlogRevisionRepositoryMemory{
+		store:     store,
+		revisions: map[string]map[int]entity.BlogRevision{},
+	}
+}
+
+// Create saves a revision, a second save of the same revision is entity.ErrConflict
+func (r BlogRevisionRepositoryMemory) Create(ctx context.Context, revision entity.BlogRevision) error {
+	return r.store.Write(ctx, func(undo func(func())) error {
+		blogID := revision.BlogID.String()
+		if _, ok := r.revisions[blogID][revision.Revision]; ok {
+			return entity.ErrConflict
+		}
+
+		if r.revisions[blogID] == nil {
+			r.revisions[blogID] = map[int]entity.BlogRevision{}
+		}
+		r.revisions[blogID][revision.Revision] = revision
+		undo(func() {
+			delete(r.revisions[blogID], revision.Revision)
+		})
+		return nil
+	})
+}
+
+// FindAll finds every revision of a blog, newest first
+func (r BlogRevisionRepositoryMemory) FindAll(ctx context.Context, blogID string) ([]*entity.BlogRevision, error) {
+	revisions := []*entity.BlogRevision{}
+	r.store.Read(ctx, func() {
+		for _, revision := range r.revisions[blogID] {
+			revision := revision
+			revisions = append(revisions, &revision)
+		}
+	})
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision > revisions[j].Revision
+	})
+
+	return revisions, nil
+}
+
+// FindByRevision finds one revision of a blog
+func (r BlogRevisionRepositoryMemory) FindByRevision(ctx context.Context, blogID string, revision int) (*entity.BlogRevision, error) {
+	var (
+		found entity.BlogRevision
+		ok    bool
+	)
+	r.store.Read(ctx, func() {
+		found, ok = r.revisions[blogID][revision]
+	})
+	if !ok {
+		return nil, entity.ErrNotFound
+	}
+
+	return &found, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	model_db "github.com/rifkiadrn/cassandra-explore/internal/model/db"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BlogTagRepository keeps the blog_tags join table in Postgres
+type BlogTagRepository struct {
+	db    *gorm.DB
+	log   *logrus.Logger
+	blogs BlogRepository
+}
+
+func NewBlogTagRepository(db *gorm.DB, log *logrus.Logger) BlogTagRepository {
+	return BlogTagRepository{
+		db:    db,
+		log:   log,
+		blogs: NewBlogRepository(db, log),
+	}
+}
+
+func (r *BlogTagRepository) getDB(ctx context.Context) *gorm.DB {
+	if tx := context_db.GetTx(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+// SetTags deletes the rows of the tags the blog lost and inserts the missing ones
+func (r BlogTagRepository) SetTags(ctx context.Context, blog entity.Blog, previous []string) error {
+	tags := blog.Tags()
+
+	if removed := missingTags(previous, tags); len(removed) > 0 {
+		if err := r.getDB(ctx).Where("blog_id = ? AND tag IN ?", blog.ID, removed).Delete(&model_db.BlogTag{}).Error; err != nil {
+			return err
+		}
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	dbTags := make([]model_db.BlogTag, len(tags))
+	for i, tag := range tags {
+		dbTags[i] = model_db.BlogTag{BlogID: blog.ID, Tag: tag, Ts: blog.Ts.Unix()}
+	}
+	return r.getDB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&dbTags).Error
+}
+
+// Remove deletes every tag row of the blog, the blog row itself is only soft deleted
+func (r BlogTagRepository) Remove(ctx context.Context, blog entity.Blog) error {
+	return r.getDB(ctx).Where("blog_id = ?", blog.ID).Delete(&model_db.BlogTag{}).Error
+}
+
+// FindByTag finds one page of the blogs of some authors under a tag, newest first
+func (r BlogTagRepository) FindByTag(ctx context.Context, tag string, authorIDs []uuid.UUID, page entity.Page) ([]*entity.Blog, string, error) {
+	after, err := entity.DecodeFeedPosition(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := r.getDB(ctx).Model(&model_db.Blog{}).Select("blogs.*").
+		Joins("JOIN blog_tags ON blog_tags.blog_id = blogs.id").
+		Scopes(notDeleted, notExpired).
+		Where("blog_tags.tag = ? AND blogs.user_id IN ?", tag, authorIDs)
+	if !after.IsZero() {
+		query = query.Where("(blog_tags.ts, blog_tags.blog_id) < (?, ?)", after.Ts.Unix(), after.ID)
+	}
+
+	// fetch one extra row to know whether there is a next page
+	var dbBlogs []model_db.Blog
+	if err := query.Order("blog_tags.ts DESC, blog_tags.blog_id DESC").Limit(page.Limit + 1).Find(&dbBlogs).Error; err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(dbBlogs) > page.Limit {
+		dbBlogs = dbBlogs[:page.Limit]
+		last := dbBlogs[len(dbBlogs)-1]
+		nextCursor = entity.FeedPosition{Ts: time.Unix(last.Ts, 0), ID: last.ID}.Encode()
+	}
+
+	blogs := make([]*entity.Blog, len(dbBlogs))
+	for i, dbBlog := range dbBlogs {
+		blogs[i] = r.blogs.dbToEntityBlog(dbBlog)
+	}
+
+	return blogs, nextCursor, nil
+}
+
+// TopTags counts the visible blogs written since the time under each tag, most used first
+func (r BlogTagRepository) TopTags(ctx context.Context, since time.Time, limit int) ([]entity.TagCount, error) {
+	var counts []entity.TagCount
+	err := r.getDB(ctx).Model(&model_db.BlogTag{}).Select("blog_tags.tag, COUNT(*) AS blogs").
+		Joins("JOIN blogs ON blogs.id = blog_tags.blog_id").
+		Scopes(notDeleted, notExpired).
+		Where("blog_tags.ts >= ?", since.Unix()).
+		Group("blog_tags.tag").
+		Order("COUNT(*) DESC, blog_tags.tag").
+		Limit(limit).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// missingTags returns the tags of from that are not in to
+func missingTags(from []string, to []string) []string {
+	kept := make(map[string]bool, len(to))
+	for _, tag := range to {
+		kept[tag] = true
+	}
+
+	var missing []string
+	for _, tag := range from {
+		if !kept[tag] {
+			missing = append(missing, tag)
+		}
+	}
+	return missing
+}
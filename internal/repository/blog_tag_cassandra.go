@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/google/uuid"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
+)
+
+// BlogTagRepositoryNoSQL files a copy of each blog under its tags and counts tags per UTC day
+type BlogTagRepositoryNoSQL struct {
+	db     NoSQLSession
+	bucket string
+	log    *logrus.Logger
+}
+
+func NewBlogTagRepositoryNoSQL(db NoSQLSession, bucket string, log *logrus.Logger) BlogTagRepositoryNoSQL {
+	return BlogTagRepositoryNoSQL{
+		db:     db,
+		bucket: bucket,
+		log:    log,
+	}
+}
+
+// tagDay names the tag_counts_by_day partition of a blog time
+func tagDay(ts time.Time) string {
+	return ts.UTC().Format(time.DateOnly)
+}
+
+// SetTags writes the blog under each of its tags and removes it from the tags it lost
+func (r BlogTagRepositoryNoSQL) SetTags(ctx context.Context, blog entity.Blog, previous []string) error {
+	tags := blog.Tags()
+
+	for _, tag := range missingTags(previous, tags) {
+		if err := r.removeTag(ctx, blog, tag); err != nil {
+			return err
+		}
+	}
+
+	bucket := blogBucket(blog.Ts, r.bucket)
+	for _, tag := range tags {
+		if err := execNoSQL(ctx, r.db, idempotent, `INSERT INTO blogs_by_tag (tag, bucket, ts, id, author_id, username, content, expires_at, revision) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?`,
+			tag, bucket, blogTimeUUID(blog), gocql.UUID(blog.ID), gocql.UUID(blog.AuthorID), blog.Username, blog.Content, blogExpiresAt(blog), blogRevision(blog.Revision), blogTTL(blog)); err != nil {
+			return err
+		}
+
+		if err := execNoSQL(ctx, r.db, idempotent, `INSERT INTO blog_buckets_by_tag (tag, bucket) VALUES (?, ?)`, tag, bucket); err != nil {
+			return err
+		}
+	}
+
+	for _, tag := range missingTags(tags, previous) {
+		if err := r.count(ctx, blog, tag, 1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Remove deletes the copies of a deleted blog and uncounts its tags
+func (r BlogTagRepositoryNoSQL) Remove(ctx context.Context, blog entity.Blog) error {
+	for _, tag := range blog.Tags() {
+		if err := r.removeTag(ctx, blog, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeTag deletes the copy of the blog under one tag and uncounts it
+func (r BlogTagRepositoryNoSQL) removeTag(ctx context.Context, blog entity.Blog, tag string) error {
+	if err := execNoSQL(ctx, r.db, idempotent, `DELETE FROM blogs_by_tag WHERE tag = ? AND bucket = ? AND ts = ?`,
+		tag, blogBucket(blog.Ts, r.bucket), blogTimeUUID(blog)); err != nil {
+		return err
+	}
+
+	return r.count(ctx, blog, tag, -1)
+}
+
+// count adds delta to the tag's counter on the blog's day once the unit of work of ctx commits
+func (r BlogTagRepositoryNoSQL) count(ctx context.Context, blog entity.Blog, tag string, delta int64) error {
+	count := func() error {
+		return queryNoSQL(ctx, r.db, notIdempotent, `UPDATE tag_counts_by_day SET blogs = blogs + ? WHERE day = ? AND tag = ?`,
+			delta, tagDay(blog.Ts), tag).ExecContext(ctx)
+	}
+	if !context_db.InTransaction(ctx) {
+		return count()
+	}
+
+	context_db.AfterCommit(ctx, func() {
+		if err := count(); err != nil {
+			r.log.Warnf("Failed to count tag %s of blog %s : %+v", tag, blog.ID, err)
+		}
+	})
+	return nil
+}
+
+// FindByTag finds one page of the blogs of some authors under a tag, newest first, skipping other authors
+func (r BlogTagRepositoryNoSQL) FindByTag(ctx context.Context, tag string, authorIDs []uuid.UUID, page entity.Page) ([]*entity.Blog, string, error) {
+	cursor, err := decodeBucketCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	from := ""
+	var pageState []byte
+	if cursor != nil {
+		from = cursor.Bucket
+		pageState = cursor.PageState
+	}
+	buckets, err := r.findBuckets(ctx, tag, from)
+	if err != nil {
+		return nil, "", err
+	}
+
+	authors := make(map[uuid.UUID]bool, len(authorIDs))
+	for _, id := range authorIDs {
+		authors[id] = true
+	}
+
+	var blogs []*entity.Blog
+	for i, bucket := range buckets {
+		for {
+			// Setting the page state disables auto paging, so the iterator stops after one page
+			iter := queryNoSQL(ctx, r.db, idempotent, `SELECT author_id, username, id, content, ts, expires_at, revision FROM blogs_by_tag WHERE tag = ? AND bucket = ?`, tag, bucket).
+				PageSize(page.Limit - len(blogs)).
+				PageState(pageState).
+				IterContext(ctx)
+			nextPageState := iter.PageState()
+
+			for _, blog := range scanBlogs(iter) {
+				if authors[blog.AuthorID] {
+					blogs = append(blogs, blog)
+				}
+			}
+			if err := iter.Close(); err != nil {
+				return nil, "", err
+			}
+
+			pageState = nextPageState
+			if len(pageState) == 0 {
+				break
+			}
+			if len(blogs) >= page.Limit {
+				return blogs, bucketCursor{Bucket: bucket, PageState: pageState}.encode(), nil
+			}
+		}
+
+		if len(blogs) >= page.Limit {
+			if i+1 < len(buckets) {
+				return blogs, bucketCursor{Bucket: buckets[i+1]}.encode(), nil
+			}
+			return blogs, "", nil
+		}
+	}
+
+	return blogs, "", nil
+}
+
+// findBuckets lists a tag's buckets newest first, starting at from when it is set
+func (r BlogTagRepositoryNoSQL) findBuckets(ctx context.Context, tag string, from string) ([]string, error) {
+	query := queryNoSQL(ctx, r.db, idempotent, `SELECT bucket FROM blog_buckets_by_tag WHERE tag = ?`, tag)
+	if from != "" {
+		query = queryNoSQL(ctx, r.db, idempotent, `SELECT bucket FROM blog_buckets_by_tag WHERE tag = ? AND bucket <= ?`, tag, from)
+	}
+	iter := query.IterContext(ctx)
+
+	var (
+		buckets []string
+		bucket  string
+	)
+	for iter.Scan(&bucket) {
+		buckets = append(buckets, bucket)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("find tag buckets: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// TopTags sums the day counters from the day of since to today
+func (r BlogTagRepositoryNoSQL) TopTags(ctx context.Context, since time.Time, limit int) ([]entity.TagCount, error) {
+	totals := map[string]int64{}
+	today := tagDay(time.Now())
+	for day := since.UTC(); ; day = day.AddDate(0, 0, 1) {
+		iter := queryNoSQL(ctx, r.db, idempotent, `SELECT tag, blogs FROM tag_counts_by_day WHERE day = ?`, tagDay(day)).IterContext(ctx)
+
+		var (
+			tag   string
+			blogs int64
+		)
+		for iter.Scan(&tag, &blogs) {
+			totals[tag] += blogs
+		}
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+
+		if tagDay(day) >= today {
+			break
+		}
+	}
+
+	return topTagCounts(totals, limit), nil
+}
+
+// topTagCounts keeps the limit most used tags
+func topTagCounts(totals map[string]int64, limit int) []entity.TagCount {
+	counts := make([]entity.TagCount, 0, len(totals))
+	for tag, blogs := range totals {
+		if blogs > 0 {
+			counts = append(counts, entity.TagCount{Tag: tag, Blogs: blogs})
+		}
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Blogs != counts[j].Blogs {
+			return counts[i].Blogs > counts[j].Blogs
+		}
+		return counts[i].Tag < counts[j].Tag
+	})
+	if len(counts) > limit {
+		counts = counts[:limit]
+	}
+
+	return counts
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+// BlogTagRepositoryDual files blogs under their tags in both stores, like BlogRepositoryDual
+type BlogTagRepositoryDual struct {
+	primary   usecase.IBlogTagRepo
+	secondary usecase.IBlogTagRepo
+	log       *logrus.Logger
+}
+
+func NewBlogTagRepositoryDual(primary usecase.IBlogTagRepo, secondary usecase.IBlogTagRepo, log *logrus.Logger) BlogTagRepositoryDual {
+	return BlogTagRepositoryDual{
+		primary:   primary,
+		secondary: secondary,
+		log:       log,
+	}
+}
+
+// SetTags files the blog in the primary store, then in the secondary store
+func (r BlogTagRepositoryDual) SetTags(ctx context.Context, blog entity.Blog, previous []string) error {
+	if err := r.primary.SetTags(ctx, blog, previous); err != nil {
+		return err
+	}
+
+	context_db.AfterCommit(ctx, func() {
+		if err := r.secondary.SetTags(ctx, blog, previous); err != nil {
+			r.log.Warnf("Failed to copy tags of blog %s to secondary store : %+v", blog.ID, err)
+		}
+	})
+
+	return nil
+}
+
+// Remove takes the blog off its tags in the primary store, then in the secondary store
+func (r BlogTagRepositoryDual) Remove(ctx context.Context, blog entity.Blog) error {
+	if err := r.primary.Remove(ctx, blog); err != nil {
+		return err
+	}
+
+	context_db.AfterCommit(ctx, func() {
+		if err := r.secondary.Remove(ctx, blog); err != nil {
+			r.log.Warnf("Failed to remove tags of blog %s from secondary store : %+v", blog.ID, err)
+		}
+	})
+
+	return nil
+}
+
+// FindByTag reads from the primary store
+func (r BlogTagRepositoryDual) FindByTag(ctx context.Context, tag string, authorIDs []uuid.UUID, page entity.Page) ([]*entity.Blog, string, error) {
+	return r.primary.FindByTag(ctx, tag, authorIDs, page)
+}
+
+// TopTags reads from the primary store
+func (r BlogTagRepositoryDual) TopTags(ctx context.Context, since time.Time, limit int) ([]entity.TagCount, error) {
+	return r.primary.TopTags(ctx, since, limit)
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// BlogTagRepositoryMemory keeps a copy of each blog under its tags in process, like blogs_by_tag
+type BlogTagRepositoryMemory struct {
+	store *context_db.MemoryStore
+	tags  map[string]map[string]entity.Blog
+}
+
+func NewBlogTagRepositoryMemory(store *context_db.MemoryStore) BlogTagRepositoryMemory {
+	return BlogTagRepositoryMemory{
+		store: store,
+		tags:  map[string]map[string]entity.Blog{},
+	}
+}
+
+// SetTags files the blog under its tags and removes it from the tags it lost
+func (r BlogTagRepositoryMemory) SetTags(ctx context.Context, blog entity.Blog, previous []string) error {
+	tags := blog.Tags()
+
+	return r.store.Write(ctx, func(undo func(func())) error {
+		for _, tag := range missingTags(previous, tags) {
+			r.remove(undo, tag, blog.ID.String())
+		}
+		for _, tag := range tags {
+			r.set(undo, tag, blog)
+		}
+		return nil
+	})
+}
+
+// Remove takes a deleted blog off its tags
+func (r BlogTagRepositoryMemory) Remove(ctx context.Context, blog entity.Blog) error {
+	return r.store.Write(ctx, func(undo func(func())) error {
+		for _, tag := range blog.Tags() {
+			r.remove(undo, tag, blog.ID.String())
+		}
+		return nil
+	})
+}
+
+// set files a copy of the blog under a tag, the caller holds the store
+func (r BlogTagRepositoryMemory) set(undo func(func()), tag string, blog entity.Blog) {
+	id := blog.ID.String()
+	blogs, ok := r.tags[tag]
+	if !ok {
+		tag = strings.Clone(tag)
+		blogs = map[string]entity.Blog{}
+		r.tags[tag] = blogs
+	}
+
+	stored, existed := blogs[id]
+	blogs[id] = blog
+	undo(func() {
+		if existed {
+			blogs[id] = stored
+			return
+		}
+		delete(blogs, id)
+	})
+}
+
+// remove takes a blog off a tag, the caller holds the store
+func (r BlogTagRepositoryMemory) remove(undo func(func()), tag string, id string) {
+	blogs := r.tags[tag]
+	stored, ok := blogs[id]
+	if !ok {
+		return
+	}
+
+	delete(blogs, id)
+	undo(func() {
+		blogs[id] = stored
+	})
+}
+
+// FindByTag finds one page of the blogs of some authors under a tag, newest first
+func (r BlogTagRepositoryMemory) FindByTag(ctx context.Context, tag string, authorIDs []uuid.UUID, page entity.Page) ([]*entity.Blog, string, error) {
+	after, err := entity.DecodeFeedPosition(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+
+	authors := make(map[uuid.UUID]bool, len(authorIDs))
+	for _, id := range authorIDs {
+		authors[id] = true
+	}
+
+	var blogs []*entity.Blog
+	r.store.Read(ctx, func() {
+		for _, blog := range r.tags[tag] {
+			if blogExpired(blog, now) || !authors[blog.AuthorID] {
+				continue
+			}
+			if !after.IsZero() && !olderThan(blog, after) {
+				continue
+			}
+			blog := blog
+			blogs = append(blogs, &blog)
+		}
+	})
+
+	sortNewestFirst(blogs)
+
+	nextCursor := ""
+	if len(blogs) > page.Limit {
+		blogs = blogs[:page.Limit]
+		last := blogs[len(blogs)-1]
+		nextCursor = entity.FeedPositionOf(*last).Encode()
+	}
+
+	return blogs, nextCursor, nil
+}
+
+// TopTags counts the unexpired blogs written since the time under each tag, most used first
+func (r BlogTagRepositoryMemory) TopTags(ctx context.Context, since time.Time, limit int) ([]entity.TagCount, error) {
+	now := time.Now()
+
+	totals := map[string]int64{}
+	r.store.Read(ctx, func() {
+		for tag, blogs := range r.tags {
+			for _, blog := range blogs {
+				if !blog.Ts.Before(since) && !blogExpired(blog, now) {
+					totals[tag]++
+				}
+			}
+		}
+	})
+
+	return topTagCounts(totals, limit), nil
+}
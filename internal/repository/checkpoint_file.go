@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// FileCheckpointStore keeps a JSON checkpoint in a local file
+type FileCheckpointStore struct {
+	path string
+}
+
+func NewFileCheckpointStore(path string) FileCheckpointStore {
+	return FileCheckpointStore{
+		path: path,
+	}
+}
+
+// Load reads the checkpoint into checkpoint, it returns false when there is none yet
+func (s FileCheckpointStore) Load(ctx context.Context, checkpoint interface{}) (bool, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Save writes the checkpoint through a temporary file, so a crash never leaves a torn checkpoint
+func (s FileCheckpointStore) Save(ctx context.Context, checkpoint interface{}) error {
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
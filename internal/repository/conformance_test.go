@@ -0,0 +1,136 @@
+package repository_test
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/rifkiadrn/cassandra-explore/config"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/repository"
+	"github.com/rifkiadrn/cassandra-explore/internal/repository/repositorytest"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestUserRepositoryMemory(t *testing.T) {
+	repositorytest.RunUserRepo(t, func(t *testing.T) usecase.IUserRepo {
+		return repository.NewUserRepositoryMemory(context_db.NewMemoryStore())
+	})
+}
+
+func TestBlogRepositoryMemory(t *testing.T) {
+	repositorytest.RunBlogRepo(t, func(t *testing.T) usecase.IBlog {
+		return repository.NewBlogRepositoryMemory(context_db.NewMemoryStore())
+	})
+}
+
+func TestBlogRepositoryIndexed(t *testing.T) {
+	repositorytest.RunBlogRepo(t, func(t *testing.T) usecase.IBlog {
+		return repository.NewBlogRepositoryIndexed(repository.NewBlogRepositoryMemory(context_db.NewMemoryStore()),
+			repository.NewSearchIndexMemory(), logrus.New())
+	})
+}
+
+// The database backed repositories run against the databases of config.json, migrated up, when
+// CONFORMANCE_DATABASES is set, e.g. CONFORMANCE_DATABASES=1 go test ./internal/repository
+
+func TestUserRepository(t *testing.T) {
+	db, _ := conformanceDatabases(t)
+	repositorytest.RunUserRepo(t, func(t *testing.T) usecase.IUserRepo {
+		return repository.NewUserRepository(db, logrus.New())
+	})
+}
+
+func TestBlogRepository(t *testing.T) {
+	db, _ := conformanceDatabases(t)
+	repositorytest.RunBlogRepo(t, func(t *testing.T) usecase.IBlog {
+		return repository.NewBlogRepository(db, logrus.New())
+	})
+}
+
+func TestUserRepositoryNoSQL(t *testing.T) {
+	_, noSQLDB := conformanceDatabases(t)
+	repositorytest.RunUserRepo(t, func(t *testing.T) usecase.IUserRepo {
+		return repository.NewUserRepositoryNoSQL(noSQLDB, logrus.New())
+	})
+}
+
+func TestBlogRepositoryNoSQL(t *testing.T) {
+	_, noSQLDB := conformanceDatabases(t)
+	repositorytest.RunBlogRepo(t, func(t *testing.T) usecase.IBlog {
+		return repository.NewBlogRepositoryNoSQL(noSQLDB, repository.BucketMonth)
+	})
+}
+
+// The dual backends are built the way config wires them for storage.* = dual
+
+func TestUserRepositoriesDual(t *testing.T) {
+	db, noSQLDB := conformanceDatabases(t)
+	viperConfig := viper.New()
+	viperConfig.Set("storage.users", config.StorageDual)
+
+	t.Run("Primary", func(t *testing.T) {
+		repositorytest.RunUserRepo(t, func(t *testing.T) usecase.IUserRepo {
+			primary, _ := config.NewUserRepositories(viperConfig, db, noSQLDB, logrus.New())
+			return primary
+		})
+	})
+	t.Run("Copy", func(t *testing.T) {
+		repositorytest.RunUserRepo(t, func(t *testing.T) usecase.IUserRepo {
+			_, noSQLRepo := config.NewUserRepositories(viperConfig, db, noSQLDB, logrus.New())
+			repo, ok := noSQLRepo.(usecase.IUserRepo)
+			require.True(t, ok, "the Cassandra copy of users is a full user repository")
+			return repo
+		})
+	})
+}
+
+func TestBlogRepositoryDual(t *testing.T) {
+	db, noSQLDB := conformanceDatabases(t)
+	viperConfig := viper.New()
+	viperConfig.Set("storage.blogs", config.StorageDual)
+
+	repositorytest.RunBlogRepo(t, func(t *testing.T) usecase.IBlog {
+		return config.NewBlogRepository(viperConfig, db, noSQLDB, logrus.New())
+	})
+}
+
+var (
+	databasesOnce sync.Once
+	databases     struct {
+		db      *gorm.DB
+		noSQLDB repository.NoSQLSession
+		err     error
+	}
+)
+
+// conformanceDatabases connects once to the configured databases, the test is skipped unless CONFORMANCE_DATABASES is set
+func conformanceDatabases(t *testing.T) (*gorm.DB, repository.NoSQLSession) {
+	t.Helper()
+
+	if os.Getenv("CONFORMANCE_DATABASES") == "" {
+		t.Skip("CONFORMANCE_DATABASES is not set")
+	}
+
+	databasesOnce.Do(func() {
+		viperConfig := viper.New()
+		viperConfig.SetConfigFile("../../config.json")
+		if databases.err = viperConfig.ReadInConfig(); databases.err != nil {
+			return
+		}
+
+		// the Open variants return connection errors, the New ones would exit the test binary
+		log := config.NewLogger(viperConfig)
+		if databases.db, databases.err = config.OpenDatabase(viperConfig, log, nil); databases.err != nil {
+			return
+		}
+		databases.noSQLDB, databases.err = config.OpenNoSQLDatabase(viperConfig, log, nil)
+	})
+	require.NoError(t, databases.err)
+
+	return databases.db, databases.noSQLDB
+}
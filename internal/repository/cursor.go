@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// encodeCursor wraps a store specific paging position into an opaque url-safe cursor
+func encodeCursor(raw []byte) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor unwraps a cursor made by encodeCursor, an empty cursor means the first page
+func decodeCursor(cursor string) ([]byte, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(raw) == 0 {
+		return nil, entity.ErrInvalidCursor
+	}
+
+	return raw, nil
+}
+
+// offsetCursor is the number of results already returned, for listings without a keyset
+type offsetCursor int
+
+func (o offsetCursor) encode() string {
+	return encodeCursor([]byte(strconv.Itoa(int(o))))
+}
+
+func decodeOffsetCursor(cursor string) (offsetCursor, error) {
+	raw, err := decodeCursor(cursor)
+	if err != nil || raw == nil {
+		return 0, err
+	}
+
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, entity.ErrInvalidCursor
+	}
+
+	return offsetCursor(offset), nil
+}
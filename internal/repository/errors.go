@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"errors"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"gorm.io/gorm"
+)
+
+// notFound maps the driver specific not found errors to entity.ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, gocql.ErrNotFound) {
+		return entity.ErrNotFound
+	}
+	return err
+}
+
+// conflict maps a unique violation to entity.ErrConflict
+func conflict(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return entity.ErrConflict
+	}
+	return err
+}
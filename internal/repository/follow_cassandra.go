@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/google/uuid"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// FollowRepositoryNoSQL keeps both directions of a follow plus a follower counter
+type FollowRepositoryNoSQL struct {
+	db NoSQLSession
+}
+
+func NewFollowRepositoryNoSQL(db NoSQLSession) FollowRepositoryNoSQL {
+	return FollowRepositoryNoSQL{
+		db: db,
+	}
+}
+
+// Follow records that followerID follows followedID, counting a repeated follow once
+func (r FollowRepositoryNoSQL) Follow(ctx context.Context, followerID string, followedID string) error {
+	followerId, err := gocql.ParseUUID(followerID)
+	if err != nil {
+		return err
+	}
+	followedId, err := gocql.ParseUUID(followedID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	// a retried LWT reports not applied when the first attempt landed, which would skip the counter
+	applied, err := queryNoSQL(ctx, r.db, notIdempotent, `INSERT INTO following_by_user (user_id, followed_id, followed_at) VALUES (?, ?, ?) IF NOT EXISTS`,
+		followerId, followedId, now).MapScanCASContext(ctx, map[string]interface{}{})
+	if err != nil || !applied {
+		return err
+	}
+
+	if err := queryNoSQL(ctx, r.db, idempotent, `INSERT INTO followers_by_user (user_id, follower_id, followed_at) VALUES (?, ?, ?)`,
+		followedId, followerId, now).ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return queryNoSQL(ctx, r.db, notIdempotent, `UPDATE follower_counts SET followers = followers + 1 WHERE user_id = ?`, followedId).ExecContext(ctx)
+}
+
+// Unfollow removes a follow, unfollowing twice is a no-op
+func (r FollowRepositoryNoSQL) Unfollow(ctx context.Context, followerID string, followedID string) error {
+	followerId, err := gocql.ParseUUID(followerID)
+	if err != nil {
+		return err
+	}
+	followedId, err := gocql.ParseUUID(followedID)
+	if err != nil {
+		return err
+	}
+
+	applied, err := queryNoSQL(ctx, r.db, notIdempotent, `DELETE FROM following_by_user WHERE user_id = ? AND followed_id = ? IF EXISTS`,
+		followerId, followedId).MapScanCASContext(ctx, map[string]interface{}{})
+	if err != nil || !applied {
+		return err
+	}
+
+	if err := queryNoSQL(ctx, r.db, idempotent, `DELETE FROM followers_by_user WHERE user_id = ? AND follower_id = ?`,
+		followedId, followerId).ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return queryNoSQL(ctx, r.db, notIdempotent, `UPDATE follower_counts SET followers = followers - 1 WHERE user_id = ?`, followedId).ExecContext(ctx)
+}
+
+// FindFollowers finds one page of a user's followers, resuming from the driver paging state
+func (r FollowRepositoryNoSQL) FindFollowers(ctx context.Context, userID string, page entity.Page) ([]uuid.UUID, string, error) {
+	userId, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	pageState, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	iter := queryNoSQL(ctx, r.db, idempotent, `SELECT follower_id FROM followers_by_user WHERE user_id = ?`, userId).
+		PageSize(page.Limit).
+		PageState(pageState).
+		IterContext(ctx)
+	nextPageState := iter.PageState()
+
+	var (
+		followers  []uuid.UUID
+		followerId gocql.UUID
+	)
+	for iter.Scan(&followerId) {
+		followers = append(followers, uuid.UUID(followerId))
+	}
+	if err := iter.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return followers, encodeCursor(nextPageState), nil
+}
+
+// FindFollowing finds every account a user follows
+func (r FollowRepositoryNoSQL) FindFollowing(ctx context.Context, userID string) ([]uuid.UUID, error) {
+	userId, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := queryNoSQL(ctx, r.db, idempotent, `SELECT followed_id FROM following_by_user WHERE user_id = ?`, userId).IterContext(ctx)
+
+	var (
+		following  []uuid.UUID
+		followedId gocql.UUID
+	)
+	for iter.Scan(&followedId) {
+		following = append(following, uuid.UUID(followedId))
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return following, nil
+}
+
+// CountFollowers reads the follower counter, a user nobody followed yet has no row
+func (r FollowRepositoryNoSQL) CountFollowers(ctx context.Context, userID string) (int64, error) {
+	userId, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var followers int64
+	err = queryNoSQL(ctx, r.db, idempotent, `SELECT followers FROM follower_counts WHERE user_id = ?`, userId).ScanContext(ctx, &followers)
+	if errors.Is(notFound(err), entity.ErrNotFound) {
+		return 0, nil
+	}
+	return followers, err
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// FollowRepositoryMemory keeps both directions of a follow in process
+type FollowRepositoryMemory struct {
+	store     *context_db.MemoryStore
+	following map[string]map[string]bool
+	followers map[string]map[string]bool
+}
+
+func NewFollowRepositoryMemory(store *context_db.MemoryStore) FollowRepositoryMemory {
+	return FollowRepositoryMemory{
+		store:     store,
+		following: map[string]map[string]bool{},
+		followers: map[string]map[string]bool{},
+	}
+}
+
+// Follow records that followerID follows followedID, following twice is a no-op
+func (r FollowRepositoryMemory) Follow(ctx context.Context, followerID string, followedID string) error {
+	// IDs from fiber params point into a buffer reused by the next request, map keys must own their bytes
+	followerID, followedID = strings.Clone(followerID), strings.Clone(followedID)
+
+	return r.store.Write(ctx, func(undo func(func())) error {
+		if r.following[followerID][followedID] {
+			return nil
+		}
+
+		if r.following[followerID] == nil {
+			r.following[followerID] = map[string]bool{}
+		}
+		if r.followers[followedID] == nil {
+			r.followers[followedID] = map[string]bool{}
+		}
+		r.following[followerID][followedID] = true
+		r.followers[followedID][followerID] = true
+		undo(func() {
+			delete(r.following[followerID], followedID)
+			delete(r.followers[followedID], followerID)
+		})
+		return nil
+	})
+}
+
+// Unfollow removes a follow, unfollowing twice is a no-op
+func (r FollowRepositoryMemory) Unfollow(ctx context.Context, followerID string, followedID string) error {
+	return r.store.Write(ctx, func(undo func(func())) error {
+		if !r.following[followerID][followedID] {
+			return nil
+		}
+
+		delete(r.following[followerID], followedID)
+		delete(r.followers[followedID], followerID)
+		undo(func() {
+			r.following[followerID][followedID] = true
+			r.followers[followedID][followerID] = true
+		})
+		return nil
+	})
+}
+
+// FindFollowers finds one page of a user's followers in ID order
+func (r FollowRepositoryMemory) FindFollowers(ctx context.Context, userID string, page entity.Page) ([]uuid.UUID, string, error) {
+	after, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var ids []string
+	r.store.Read(ctx, func() {
+		for id := range r.followers[userID] {
+			if id > string(after) {
+				ids = append(ids, id)
+			}
+		}
+	})
+
+	sort.Strings(ids)
+	nextCursor := ""
+	if page.Limit > 0 && len(ids) > page.Limit {
+		ids = ids[:page.Limit]
+		nextCursor = encodeCursor([]byte(ids[len(ids)-1]))
+	}
+
+	followers, err := parseUUIDs(ids)
+	return followers, nextCursor, err
+}
+
+// FindFollowing finds every account a user follows
+func (r FollowRepositoryMemory) FindFollowing(ctx context.Context, userID string) ([]uuid.UUID, error) {
+	var ids []string
+	r.store.Read(ctx, func() {
+		for id := range r.following[userID] {
+			ids = append(ids, id)
+		}
+	})
+
+	return parseUUIDs(ids)
+}
+
+// CountFollowers counts a user's followers
+func (r FollowRepositoryMemory) CountFollowers(ctx context.Context, userID string) (int64, error) {
+	var followers int64
+	r.store.Read(ctx, func() {
+		followers = int64(len(r.followers[userID]))
+	})
+	return followers, nil
+}
+
+func parseUUIDs(ids []string) ([]uuid.UUID, error) {
+	parsed := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		uid, err := uuid.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = uid
+	}
+	return parsed, nil
+}
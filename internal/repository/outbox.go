@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	model_db "github.com/rifkiadrn/cassandra-explore/internal/model/db"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxRepository stores outbox events in Postgres, next to the rows they describe
+type OutboxRepository struct {
+	db  *gorm.DB
+	log *logrus.Logger
+}
+
+func NewOutboxRepository(db *gorm.DB, log *logrus.Logger) OutboxRepository {
+	return OutboxRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *OutboxRepository) getDB(ctx context.Context) *gorm.DB {
+	if tx := context_db.GetTx(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+// entityToDBOutboxEvent converts domain entity to DB model
+func (r OutboxRepository) entityToDBOutboxEvent(e entity.OutboxEvent) model_db.OutboxEvent {
+	return model_db.OutboxEvent{
+		ID:            e.ID,
+		AggregateID:   e.AggregateID,
+		EventType:     e.EventType,
+		Payload:       string(e.Payload),
+		Status:        e.Status,
+		Attempts:      e.Attempts,
+		LastError:     e.LastError,
+		NextAttemptAt: e.NextAttemptAt.Unix(),
+	}
+}
+
+// dbToEntityOutboxEvent converts DB model to domain entity pointer
+func (r OutboxRepository) dbToEntityOutboxEvent(db model_db.OutboxEvent) *entity.OutboxEvent {
+	return &entity.OutboxEvent{
+		ID:            db.ID,
+		AggregateID:   db.AggregateID,
+		EventType:     db.EventType,
+		Payload:       []byte(db.Payload),
+		Status:        db.Status,
+		Attempts:      db.Attempts,
+		LastError:     db.LastError,
+		NextAttemptAt: time.Unix(db.NextAttemptAt, 0),
+		CreatedAt:     time.Unix(db.CreatedAt, 0),
+	}
+}
+
+// Create records an outbox event, inside the caller's transaction when there is one
+func (r OutboxRepository) Create(ctx context.Context, event entity.OutboxEvent) (*entity.OutboxEvent, error) {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.Status == "" {
+		event.Status = entity.OutboxStatusPending
+	}
+	if event.NextAttemptAt.IsZero() {
+		event.NextAttemptAt = time.Now()
+	}
+
+	dbEvent := r.entityToDBOutboxEvent(event)
+
+	if err := r.getDB(ctx).Create(&dbEvent).Error; err != nil {
+		return nil, err
+	}
+
+	return r.dbToEntityOutboxEvent(dbEvent), nil
+}
+
+// FindPending locks up to limit due pending events, rows locked by another relay are skipped
+func (r OutboxRepository) FindPending(ctx context.Context, limit int) ([]*entity.OutboxEvent, error) {
+	var dbEvents []model_db.OutboxEvent
+	if err := r.getDB(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", entity.OutboxStatusPending, time.Now().Unix()).
+		Order("next_attempt_at, created_at").
+		Limit(limit).
+		Find(&dbEvents).Error; err != nil {
+		return nil, err
+	}
+
+	// Convert to entities
+	events := make([]*entity.OutboxEvent, len(dbEvents))
+	for i, dbEvent := range dbEvents {
+		events[i] = r.dbToEntityOutboxEvent(dbEvent)
+	}
+
+	return events, nil
+}
+
+// Lease pushes the next attempt of the events to until, so other relays skip them meanwhile
+func (r OutboxRepository) Lease(ctx context.Context, eventIDs []uuid.UUID, until time.Time) error {
+	return r.getDB(ctx).Model(&model_db.OutboxEvent{}).
+		Where("id IN ?", eventIDs).
+		Updates(map[string]interface{}{
+			"next_attempt_at": until.Unix(),
+			"updated_at":      time.Now().Unix(),
+		}).Error
+}
+
+// UpdateStatus saves the outcome of a delivery attempt
+func (r OutboxRepository) UpdateStatus(ctx context.Context, event entity.OutboxEvent) error {
+	return r.getDB(ctx).Model(&model_db.OutboxEvent{}).
+		Where("id = ?", event.ID).
+		Updates(map[string]interface{}{
+			"status":          event.Status,
+			"attempts":        event.Attempts,
+			"last_error":      event.LastError,
+			"next_attempt_at": event.NextAttemptAt.Unix(),
+			"updated_at":      time.Now().Unix(),
+		}).Error
+}
+
+// CountByStatus counts outbox events per status
+func (r OutboxRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := r.getDB(ctx).Model(&model_db.OutboxEvent{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
+}
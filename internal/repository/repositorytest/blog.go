@@ -0,0 +1,256 @@
+package repositorytest
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// BlogRepoFactory builds the repository under test, it is called once per case
+type BlogRepoFactory func(t *testing.T) usecase.IBlog
+
+// RunBlogRepo runs the blog repository suite against the repositories newRepo builds
+func RunBlogRepo(t *testing.T, newRepo BlogRepoFactory) {
+	t.Run("CreateAndFind", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		blog := newBlog(uuid.New(), time.Now())
+		created, err := repo.Create(ctx, blog)
+		require.NoError(t, err)
+		assertSameBlog(t, blog, *created)
+
+		found, err := repo.FindById(ctx, blog.ID.String())
+		require.NoError(t, err)
+		assertSameBlog(t, blog, *found)
+	})
+
+	t.Run("CreateExpiring", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		blog := newBlog(uuid.New(), time.Now())
+		blog.ExpiresAt = time.Now().Add(time.Hour)
+		_, err := repo.Create(ctx, blog)
+		require.NoError(t, err)
+
+		found, err := repo.FindById(ctx, blog.ID.String())
+		require.NoError(t, err)
+		assertSameBlog(t, blog, *found)
+	})
+
+	t.Run("FindAllOrdering", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		authorID := uuid.New()
+		blogs := newBlogs(authorID, 7)
+		for _, i := range rand.Perm(len(blogs)) {
+			_, err := repo.Create(ctx, blogs[i])
+			require.NoError(t, err)
+		}
+		// another author's blog never shows up
+		_, err := repo.Create(ctx, newBlog(uuid.New(), time.Now()))
+		require.NoError(t, err)
+
+		assertBlogIDs(t, blogs, findAll(t, repo, authorID, 3))
+	})
+
+	t.Run("FindBefore", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		authorID := uuid.New()
+		blogs := newBlogs(authorID, 5)
+		for _, blog := range blogs {
+			_, err := repo.Create(ctx, blog)
+			require.NoError(t, err)
+		}
+
+		newest, err := repo.FindBefore(ctx, authorID.String(), entity.FeedPosition{}, 2)
+		require.NoError(t, err)
+		assertBlogIDs(t, blogs[:2], newest)
+
+		position := entity.FeedPosition{Ts: blogs[1].Ts, ID: blogs[1].ID}
+		older, err := repo.FindBefore(ctx, authorID.String(), position, 10)
+		require.NoError(t, err)
+		assertBlogIDs(t, blogs[2:], older)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		_, err := repo.FindById(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, entity.ErrNotFound)
+
+		missing := newBlog(uuid.New(), time.Now())
+		missing.Revision = 2
+		_, err = repo.Update(ctx, missing)
+		assert.ErrorIs(t, err, entity.ErrNotFound)
+
+		err = repo.Delete(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, entity.ErrNotFound)
+
+		blogs, cursor, err := repo.FindAll(ctx, uuid.NewString(), entity.Page{Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, blogs)
+		assert.Empty(t, cursor)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		blog := newBlog(uuid.New(), time.Now())
+		_, err := repo.Create(ctx, blog)
+		require.NoError(t, err)
+
+		edit := blog
+		edit.Content = "edited"
+		edit.Revision = 2
+		updated, err := repo.Update(ctx, edit)
+		require.NoError(t, err)
+		assert.Equal(t, "edited", updated.Content)
+		assert.Equal(t, 2, updated.Revision)
+
+		found, err := repo.FindById(ctx, blog.ID.String())
+		require.NoError(t, err)
+		assertSameBlog(t, edit, *found)
+
+		// an edit made on top of revision 1 again lost to the one above
+		stale := blog
+		stale.Content = "stale"
+		stale.Revision = 2
+		_, err = repo.Update(ctx, stale)
+		assert.ErrorIs(t, err, entity.ErrConflict)
+
+		found, err = repo.FindById(ctx, blog.ID.String())
+		require.NoError(t, err)
+		assertSameBlog(t, edit, *found)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		authorID := uuid.New()
+		blogs := newBlogs(authorID, 3)
+		for _, blog := range blogs {
+			_, err := repo.Create(ctx, blog)
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, repo.Delete(ctx, blogs[1].ID.String()))
+
+		_, err := repo.FindById(ctx, blogs[1].ID.String())
+		assert.ErrorIs(t, err, entity.ErrNotFound)
+		assertBlogIDs(t, []entity.Blog{blogs[0], blogs[2]}, findAll(t, repo, authorID, 10))
+
+		err = repo.Delete(ctx, blogs[1].ID.String())
+		assert.ErrorIs(t, err, entity.ErrNotFound)
+	})
+
+	t.Run("ConcurrentCreates", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		authorID := uuid.New()
+		blogs := newBlogs(authorID, concurrency)
+		errs := make([]error, len(blogs))
+		var wg sync.WaitGroup
+		for i := range blogs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = repo.Create(ctx, blogs[i])
+			}(i)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+		assertBlogIDs(t, blogs, findAll(t, repo, authorID, 5))
+	})
+}
+
+func newBlog(authorID uuid.UUID, ts time.Time) entity.Blog {
+	return entity.Blog{
+		ID:       uuid.New(),
+		AuthorID: authorID,
+		Username: "conformance",
+		Content:  "content of " + uuid.NewString(),
+		Ts:       ts.Truncate(time.Second),
+		Revision: 1,
+	}
+}
+
+// newBlogs makes n blogs of an author newest first, a minute apart so their order does not hang on IDs
+func newBlogs(authorID uuid.UUID, n int) []entity.Blog {
+	now := time.Now()
+	blogs := make([]entity.Blog, n)
+	for i := range blogs {
+		blogs[i] = newBlog(authorID, now.Add(-time.Duration(i)*time.Minute))
+	}
+	return blogs
+}
+
+// findAll reads every page of an author's blogs
+func findAll(t *testing.T, repo usecase.IBlog, authorID uuid.UUID, limit int) []*entity.Blog {
+	t.Helper()
+
+	var blogs []*entity.Blog
+	page := entity.Page{Limit: limit}
+	for {
+		result, nextCursor, err := repo.FindAll(context.Background(), authorID.String(), page)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(result), limit)
+		blogs = append(blogs, result...)
+
+		if nextCursor == "" {
+			return blogs
+		}
+		require.NotEqual(t, page.Cursor, nextCursor, "the cursor did not move")
+		page.Cursor = nextCursor
+	}
+}
+
+// assertSameBlog compares what every store keeps
+func assertSameBlog(t *testing.T, expected entity.Blog, actual entity.Blog) {
+	t.Helper()
+
+	assert.Equal(t, expected.ID, actual.ID)
+	assert.Equal(t, expected.AuthorID, actual.AuthorID)
+	assert.Equal(t, expected.Username, actual.Username)
+	assert.Equal(t, expected.Content, actual.Content)
+	assert.Equal(t, expected.Revision, actual.Revision)
+	assert.Equal(t, expected.Ts.Unix(), actual.Ts.Unix(), "ts")
+	assert.Equal(t, expected.ExpiresAt.IsZero(), actual.ExpiresAt.IsZero(), "expires_at set")
+	if !expected.ExpiresAt.IsZero() {
+		assert.Equal(t, expected.ExpiresAt.Unix(), actual.ExpiresAt.Unix(), "expires_at")
+	}
+}
+
+// assertBlogIDs checks the blogs are the expected ones in the expected order
+func assertBlogIDs(t *testing.T, expected []entity.Blog, actual []*entity.Blog) {
+	t.Helper()
+
+	expectedIDs := make([]uuid.UUID, len(expected))
+	for i, blog := range expected {
+		expectedIDs[i] = blog.ID
+	}
+	actualIDs := make([]uuid.UUID, len(actual))
+	for i, blog := range actual {
+		actualIDs[i] = blog.ID
+	}
+	assert.Equal(t, expectedIDs, actualIDs)
+}
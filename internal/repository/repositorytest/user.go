@@ -0,0 +1,214 @@
+// Package repositorytest is the conformance suite every user and blog repository has to pass
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/rifkiadrn/cassandra-explore/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrency is how many writers the concurrent cases start at once
+const concurrency = 16
+
+// UserRepoFactory builds the repository under test, it is called once per case
+type UserRepoFactory func(t *testing.T) usecase.IUserRepo
+
+// RunUserRepo runs the user repository suite against the repositories newRepo builds
+func RunUserRepo(t *testing.T, newRepo UserRepoFactory) {
+	t.Run("CreateAndFind", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		user := newUser()
+		created, err := repo.Create(ctx, user)
+		require.NoError(t, err)
+		assertSameUser(t, user, *created)
+
+		found, err := repo.FindById(ctx, user.ID.String())
+		require.NoError(t, err)
+		assertSameUser(t, user, *found)
+
+		found, err = repo.FindByUsername(ctx, user.Username)
+		require.NoError(t, err)
+		assertSameUser(t, user, *found)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		_, err := repo.FindById(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, entity.ErrNotFound)
+
+		_, err = repo.FindByUsername(ctx, newUsername())
+		assert.ErrorIs(t, err, entity.ErrNotFound)
+	})
+
+	t.Run("UsernameConflict", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		first := newUser()
+		_, err := repo.Create(ctx, first)
+		require.NoError(t, err)
+
+		second := newUser()
+		second.Username = first.Username
+		_, err = repo.Create(ctx, second)
+		assert.ErrorIs(t, err, entity.ErrConflict)
+
+		found, err := repo.FindByUsername(ctx, first.Username)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, found.ID)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		user := newUser()
+		created, err := repo.Create(ctx, user)
+		require.NoError(t, err)
+
+		// empty fields are left as they are
+		updated, err := repo.Update(ctx, *created, entity.User{Name: "Renamed"})
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed", updated.Name)
+		assert.Equal(t, user.Username, updated.Username)
+
+		found, err := repo.FindById(ctx, user.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed", found.Name)
+		assert.Equal(t, user.Password, found.Password)
+
+		// a new username frees the old one
+		username := newUsername()
+		_, err = repo.Update(ctx, *found, entity.User{Username: username})
+		require.NoError(t, err)
+
+		found, err = repo.FindByUsername(ctx, username)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, found.ID)
+
+		_, err = repo.FindByUsername(ctx, user.Username)
+		assert.ErrorIs(t, err, entity.ErrNotFound)
+	})
+
+	t.Run("UpdateUsernameConflict", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		taken := newUser()
+		_, err := repo.Create(ctx, taken)
+		require.NoError(t, err)
+
+		user := newUser()
+		created, err := repo.Create(ctx, user)
+		require.NoError(t, err)
+
+		_, err = repo.Update(ctx, *created, entity.User{Username: taken.Username})
+		assert.ErrorIs(t, err, entity.ErrConflict)
+
+		found, err := repo.FindById(ctx, user.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, user.Username, found.Username)
+
+		found, err = repo.FindByUsername(ctx, taken.Username)
+		require.NoError(t, err)
+		assert.Equal(t, taken.ID, found.ID)
+	})
+
+	t.Run("ConcurrentCreates", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		users := make([]entity.User, concurrency)
+		errs := make([]error, concurrency)
+		var wg sync.WaitGroup
+		for i := range users {
+			users[i] = newUser()
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = repo.Create(ctx, users[i])
+			}(i)
+		}
+		wg.Wait()
+
+		for i, user := range users {
+			require.NoError(t, errs[i])
+
+			found, err := repo.FindById(ctx, user.ID.String())
+			require.NoError(t, err)
+			assertSameUser(t, user, *found)
+		}
+	})
+
+	t.Run("ConcurrentUsernameClaims", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		username := newUsername()
+		users := make([]entity.User, concurrency)
+		errs := make([]error, concurrency)
+		var wg sync.WaitGroup
+		for i := range users {
+			users[i] = newUser()
+			users[i].Username = username
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = repo.Create(ctx, users[i])
+			}(i)
+		}
+		wg.Wait()
+
+		// exactly one writer gets the username, it is the user found by it
+		var winner *entity.User
+		for i, err := range errs {
+			if err == nil {
+				require.Nil(t, winner, "username %s was given out twice", username)
+				winner = &users[i]
+				continue
+			}
+			assert.True(t, errors.Is(err, entity.ErrConflict), "unexpected error: %v", err)
+		}
+		require.NotNil(t, winner)
+
+		found, err := repo.FindByUsername(ctx, username)
+		require.NoError(t, err)
+		assert.Equal(t, winner.ID, found.ID)
+	})
+}
+
+func newUsername() string {
+	return "conformance-" + uuid.NewString()
+}
+
+func newUser() entity.User {
+	return entity.User{
+		ID:       uuid.New(),
+		Name:     "Conformance",
+		Username: newUsername(),
+		Password: "hashed-" + uuid.NewString(),
+		Token:    uuid.NewString(),
+	}
+}
+
+// assertSameUser compares what every store keeps, stores set the timestamps themselves
+func assertSameUser(t *testing.T, expected entity.User, actual entity.User) {
+	t.Helper()
+
+	assert.Equal(t, expected.ID, actual.ID)
+	assert.Equal(t, expected.Name, actual.Name)
+	assert.Equal(t, expected.Username, actual.Username)
+	assert.Equal(t, expected.Password, actual.Password)
+	assert.Equal(t, expected.Token, actual.Token)
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// SearchIndexMemory is an in-process inverted index of blog content, it starts empty
+type SearchIndexMemory struct {
+	mu       *sync.RWMutex
+	postings map[string]map[uuid.UUID]int
+	blogs    map[uuid.UUID]indexedBlog
+}
+
+// indexedBlog is what the index keeps of a blog to filter, order and unindex it
+type indexedBlog struct {
+	authorID  uuid.UUID
+	ts        time.Time
+	expiresAt time.Time
+	terms     map[string]int
+}
+
+func NewSearchIndexMemory() SearchIndexMemory {
+	return SearchIndexMemory{
+		mu:       &sync.RWMutex{},
+		postings: map[string]map[uuid.UUID]int{},
+		blogs:    map[uuid.UUID]indexedBlog{},
+	}
+}
+
+// Index adds a blog, or replaces what was indexed for it
+func (s SearchIndexMemory) Index(ctx context.Context, blog entity.Blog) error {
+	indexed := indexedBlog{
+		authorID:  blog.AuthorID,
+		ts:        blog.Ts,
+		expiresAt: blog.ExpiresAt,
+		terms:     searchTerms(blog.Content),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(blog.ID)
+	s.blogs[blog.ID] = indexed
+	for term, count := range indexed.terms {
+		if s.postings[term] == nil {
+			s.postings[term] = map[uuid.UUID]int{}
+		}
+		s.postings[term][blog.ID] = count
+	}
+
+	return nil
+}
+
+// Remove unindexes a blog, an unknown blog is ignored
+func (s SearchIndexMemory) Remove(ctx context.Context, blogID string) error {
+	id, err := uuid.Parse(blogID)
+	if err != nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(id)
+	return nil
+}
+
+// remove unindexes a blog, the caller holds the write lock
+func (s SearchIndexMemory) remove(id uuid.UUID) {
+	indexed, ok := s.blogs[id]
+	if !ok {
+		return
+	}
+
+	for term := range indexed.terms {
+		delete(s.postings[term], id)
+		if len(s.postings[term]) == 0 {
+			delete(s.postings, term)
+		}
+	}
+	delete(s.blogs, id)
+}
+
+// searchHit is a matching blog with its score
+type searchHit struct {
+	id    uuid.UUID
+	ts    time.Time
+	score float64
+}
+
+// Search finds the unexpired blogs of the authors using every word of the query, best tf-idf score first
+func (s SearchIndexMemory) Search(ctx context.Context, query string, authorIDs []uuid.UUID, offset int, limit int) ([]uuid.UUID, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	authors := make(map[uuid.UUID]bool, len(authorIDs))
+	for _, id := range authorIDs {
+		authors[id] = true
+	}
+	now := time.Now()
+
+	s.mu.RLock()
+	hits, expired := s.match(terms, authors, now)
+	s.mu.RUnlock()
+
+	// expired blogs are dropped when a search comes across them
+	if len(expired) > 0 {
+		s.mu.Lock()
+		for _, id := range expired {
+			s.remove(id)
+		}
+		s.mu.Unlock()
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		if !hits[i].ts.Equal(hits[j].ts) {
+			return hits[i].ts.After(hits[j].ts)
+		}
+		return bytes.Compare(hits[i].id[:], hits[j].id[:]) > 0
+	})
+
+	if offset >= len(hits) {
+		return nil, nil
+	}
+	hits = hits[offset:]
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	ids := make([]uuid.UUID, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.id
+	}
+
+	return ids, nil
+}
+
+// match scores the authors' blogs having every term, the caller holds the read lock
+func (s SearchIndexMemory) match(terms map[string]int, authors map[uuid.UUID]bool, now time.Time) ([]searchHit, []uuid.UUID) {
+	// walk the rarest term's blogs and look the other terms up
+	var rarest map[uuid.UUID]int
+	for term := range terms {
+		postings, ok := s.postings[term]
+		if !ok {
+			return nil, nil
+		}
+		if rarest == nil || len(postings) < len(rarest) {
+			rarest = postings
+		}
+	}
+
+	var (
+		hits    []searchHit
+		expired []uuid.UUID
+	)
+blogs:
+	for id := range rarest {
+		indexed := s.blogs[id]
+		if !authors[indexed.authorID] {
+			continue
+		}
+		if !indexed.expiresAt.IsZero() && !indexed.expiresAt.After(now) {
+			expired = append(expired, id)
+			continue
+		}
+
+		score := 0.0
+		for term := range terms {
+			count, ok := s.postings[term][id]
+			if !ok {
+				continue blogs
+			}
+			idf := math.Log(1 + float64(len(s.blogs))/float64(len(s.postings[term])))
+			score += (1 + math.Log(float64(count))) * idf
+		}
+		hits = append(hits, searchHit{id: id, ts: indexed.ts, score: score})
+	}
+
+	return hits, expired
+}
+
+// searchTerms counts the lowercase words of text, like the simple Postgres search configuration
+func searchTerms(text string) map[string]int {
+	terms := map[string]int{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		terms[word]++
+	}
+	return terms
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// TableScannerNoSQL reads any table one token range at a time, rows come back as column maps
+type TableScannerNoSQL struct {
+	db       NoSQLSession
+	keyspace string // Of tables named without one
+	pageSize int
+}
+
+func NewTableScannerNoSQL(db NoSQLSession, keyspace string, pageSize int) TableScannerNoSQL {
+	return TableScannerNoSQL{
+		db:       db,
+		keyspace: keyspace,
+		pageSize: pageSize,
+	}
+}
+
+// Columns lists the columns of a table, partition key first, from the schema metadata
+func (s TableScannerNoSQL) Columns(ctx context.Context, table string) ([]string, error) {
+	metadata, err := s.tableMetadata(table)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.OrderedColumns, nil
+}
+
+// ScanRange hands every row whose partition token lies in the range to emit
+func (s TableScannerNoSQL) ScanRange(ctx context.Context, table string, tokenRange entity.TokenRange, emit func(row map[string]interface{}) error) error {
+	metadata, err := s.tableMetadata(table)
+	if err != nil {
+		return err
+	}
+
+	partitionKey := make([]string, len(metadata.PartitionKey))
+	for i, column := range metadata.PartitionKey {
+		partitionKey[i] = quoteIdentifier(column.Name)
+	}
+	columns := make([]string, len(metadata.OrderedColumns))
+	for i, column := range metadata.OrderedColumns {
+		columns[i] = quoteIdentifier(column)
+	}
+	token := "token(" + strings.Join(partitionKey, ", ") + ")"
+
+	stmt := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE %s > ? AND %s <= ?`, strings.Join(columns, ", "),
+		quoteIdentifier(metadata.Keyspace), quoteIdentifier(metadata.Name), token, token)
+	iter := queryNoSQL(ctx, s.db, idempotent, stmt, tokenRange.Start, tokenRange.End).PageSize(s.pageSize).IterContext(ctx)
+
+	for {
+		row := map[string]interface{}{}
+		if !iter.MapScan(row) {
+			break
+		}
+		if err := emit(row); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+
+	return iter.Close()
+}
+
+// tableMetadata finds a table named table or keyspace.table
+func (s TableScannerNoSQL) tableMetadata(table string) (*gocql.TableMetadata, error) {
+	keyspace := s.keyspace
+	if before, after, ok := strings.Cut(table, "."); ok {
+		keyspace, table = before, after
+	}
+
+	metadata, err := s.db.KeyspaceMetadata(keyspace)
+	if err != nil {
+		return nil, err
+	}
+	tableMetadata, ok := metadata.Tables[table]
+	if !ok {
+		return nil, fmt.Errorf("table %s.%s: %w", keyspace, table, entity.ErrNotFound)
+	}
+
+	return tableMetadata, nil
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/google/uuid"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// TimelineRepositoryNoSQL stores the materialised home timelines, one partition per reader
+type TimelineRepositoryNoSQL struct {
+	db NoSQLSession
+}
+
+func NewTimelineRepositoryNoSQL(db NoSQLSession) TimelineRepositoryNoSQL {
+	return TimelineRepositoryNoSQL{
+		db: db,
+	}
+}
+
+// Add writes a blog into a reader's timeline, keyed like blogs_by_author so fanning out again overwrites
+func (r TimelineRepositoryNoSQL) Add(ctx context.Context, readerID string, blog entity.Blog) error {
+	readerId, err := gocql.ParseUUID(readerID)
+	if err != nil {
+		return err
+	}
+
+	return execNoSQL(ctx, r.db, idempotent, `INSERT INTO timeline_by_user (user_id, ts, id, author_id, username, content, expires_at, revision) VALUES (?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?`,
+		readerId, blogTimeUUID(blog), gocql.UUID(blog.ID), gocql.UUID(blog.AuthorID), blog.Username, blog.Content, blogExpiresAt(blog), blogRevision(blog.Revision), blogTTL(blog))
+}
+
+// Remove deletes a blog from a reader's timeline, a single row tombstone
+func (r TimelineRepositoryNoSQL) Remove(ctx context.Context, readerID string, blog entity.Blog) error {
+	readerId, err := gocql.ParseUUID(readerID)
+	if err != nil {
+		return err
+	}
+
+	return execNoSQL(ctx, r.db, idempotent, `DELETE FROM timeline_by_user WHERE user_id = ? AND ts = ?`, readerId, blogTimeUUID(blog))
+}
+
+// FindBefore finds up to limit timeline blogs older than the position, newest first in feed order
+func (r TimelineRepositoryNoSQL) FindBefore(ctx context.Context, readerID string, before entity.FeedPosition, limit int) ([]*entity.Blog, error) {
+	readerId, err := gocql.ParseUUID(readerID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := queryNoSQL(ctx, r.db, idempotent, `SELECT author_id, username, id, content, ts, expires_at, revision FROM timeline_by_user WHERE user_id = ?`, readerId)
+	if !before.IsZero() {
+		query = queryNoSQL(ctx, r.db, idempotent, `SELECT author_id, username, id, content, ts, expires_at, revision FROM timeline_by_user WHERE user_id = ? AND ts < minTimeuuid(?)`,
+			readerId, nextSecond(before))
+	}
+	iter := query.PageSize(limit + 1).IterContext(ctx)
+
+	page := feedPage{before: before, limit: limit}
+	scanEachBlog(iter, page.add)
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return page.result(), nil
+}
+
+// MarkLargeAccount records that an author's blogs are no longer fanned out
+func (r TimelineRepositoryNoSQL) MarkLargeAccount(ctx context.Context, userID string) error {
+	userId, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return err
+	}
+
+	return execNoSQL(ctx, r.db, idempotent, `INSERT INTO large_accounts (shard, user_id, marked_at) VALUES (0, ?, ?)`, userId, time.Now())
+}
+
+// FindLargeAccounts finds every author marked by MarkLargeAccount
+func (r TimelineRepositoryNoSQL) FindLargeAccounts(ctx context.Context) ([]uuid.UUID, error) {
+	iter := queryNoSQL(ctx, r.db, idempotent, `SELECT user_id FROM large_accounts WHERE shard = 0`).IterContext(ctx)
+
+	var (
+		accounts []uuid.UUID
+		userId   gocql.UUID
+	)
+	for iter.Scan(&userId) {
+		accounts = append(accounts, uuid.UUID(userId))
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// TimelineRepositoryMemory stores the materialised home timelines in process
+type TimelineRepositoryMemory struct {
+	store     *context_db.MemoryStore
+	timelines map[string]map[string]entity.Blog
+	large     map[uuid.UUID]bool
+}
+
+func NewTimelineRepositoryMemory(store *context_db.MemoryStore) TimelineRepositoryMemory {
+	return TimelineRepositoryMemory{
+		store:     store,
+		timelines: map[string]map[string]entity.Blog{},
+		large:     map[uuid.UUID]bool{},
+	}
+}
+
+// Add writes a blog into a reader's timeline, fanning out again overwrites
+func (r TimelineRepositoryMemory) Add(ctx context.Context, readerID string, blog entity.Blog) error {
+	readerID = strings.Clone(readerID)
+
+	return r.store.Write(ctx, func(undo func(func())) error {
+		if r.timelines[readerID] == nil {
+			r.timelines[readerID] = map[string]entity.Blog{}
+		}
+
+		id := blog.ID.String()
+		previous, existed := r.timelines[readerID][id]
+		r.timelines[readerID][id] = blog
+		undo(func() {
+			if existed {
+				r.timelines[readerID][id] = previous
+				return
+			}
+			delete(r.timelines[readerID], id)
+		})
+		return nil
+	})
+}
+
+// Remove deletes a blog from a reader's timeline
+func (r TimelineRepositoryMemory) Remove(ctx context.Context, readerID string, blog entity.Blog) error {
+	return r.store.Write(ctx, func(undo func(func())) error {
+		id := blog.ID.String()
+		previous, existed := r.timelines[readerID][id]
+		if !existed {
+			return nil
+		}
+
+		delete(r.timelines[readerID], id)
+		undo(func() {
+			r.timelines[readerID][id] = previous
+		})
+		return nil
+	})
+}
+
+// FindBefore finds up to limit unexpired timeline blogs older than the position, newest first
+func (r TimelineRepositoryMemory) FindBefore(ctx context.Context, readerID string, before entity.FeedPosition, limit int) ([]*entity.Blog, error) {
+	now := time.Now()
+
+	var blogs []*entity.Blog
+	r.store.Read(ctx, func() {
+		for _, blog := range r.timelines[readerID] {
+			if blogExpired(blog, now) || (!before.IsZero() && !olderThan(blog, before)) {
+				continue
+			}
+			blog := blog
+			blogs = append(blogs, &blog)
+		}
+	})
+
+	sortNewestFirst(blogs)
+	if len(blogs) > limit {
+		blogs = blogs[:limit]
+	}
+
+	return blogs, nil
+}
+
+// MarkLargeAccount records that an author's blogs are no longer fanned out
+func (r TimelineRepositoryMemory) MarkLargeAccount(ctx context.Context, userID string) error {
+	userId, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	return r.store.Write(ctx, func(undo func(func())) error {
+		if r.large[userId] {
+			return nil
+		}
+
+		r.large[userId] = true
+		undo(func() {
+			delete(r.large, userId)
+		})
+		return nil
+	})
+}
+
+// FindLargeAccounts finds every author marked by MarkLargeAccount
+func (r TimelineRepositoryMemory) FindLargeAccounts(ctx context.Context) ([]uuid.UUID, error) {
+	var accounts []uuid.UUID
+	r.store.Read(ctx, func() {
+		for id := range r.large {
+			accounts = append(accounts, id)
+		}
+	})
+
+	return accounts, nil
+}
@@ -26,9 +26,9 @@ func NewUserRepository(db *gorm.DB, log *logrus.Logger) UserRepository {
 
 func (r *UserRepository) getDB(ctx context.Context) *gorm.DB {
 	if tx := context_db.GetTx(ctx); tx != nil {
-		return tx
+		return tx.WithContext(ctx)
 	}
-	return r.db
+	return r.db.WithContext(ctx)
 }
 
 // entityToDBUser converts domain entity to DB model
@@ -61,7 +61,7 @@ func (r UserRepository) Create(ctx context.Context, user entity.User) (*entity.U
 	dbUser := r.entityToDBUser(user)
 
 	if err := r.getDB(ctx).Create(&dbUser).Error; err != nil {
-		return nil, err
+		return nil, conflict(err)
 	}
 
 	return r.dbToEntityUser(dbUser), nil
@@ -71,7 +71,7 @@ func (r UserRepository) Create(ctx context.Context, user entity.User) (*entity.U
 func (r UserRepository) FindById(ctx context.Context, userID string) (*entity.User, error) {
 	var dbUser model_db.User
 	if err := r.getDB(ctx).Where("id = ?", userID).First(&dbUser).Error; err != nil {
-		return nil, err
+		return nil, notFound(err)
 	}
 
 	return r.dbToEntityUser(dbUser), nil
@@ -81,7 +81,7 @@ func (r UserRepository) FindById(ctx context.Context, userID string) (*entity.Us
 func (r UserRepository) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
 	var dbUser model_db.User
 	if err := r.getDB(ctx).Where("username = ?", username).First(&dbUser).Error; err != nil {
-		return nil, err
+		return nil, notFound(err)
 	}
 
 	return r.dbToEntityUser(dbUser), nil
@@ -113,6 +113,57 @@ func (r UserRepository) GetOnlineUsers(ctx context.Context) ([]*entity.User, err
 	return users, nil
 }
 
+// FindBatch finds up to limit users with an ID greater than afterID, in ID order
+func (r UserRepository) FindBatch(ctx context.Context, afterID string, limit int) ([]*entity.User, error) {
+	query := r.getDB(ctx)
+	if afterID != "" {
+		query = query.Where("id > ?", afterID)
+	}
+
+	var dbUsers []model_db.User
+	if err := query.Order("id").Limit(limit).Find(&dbUsers).Error; err != nil {
+		return nil, err
+	}
+
+	// Convert to entities
+	users := make([]*entity.User, len(dbUsers))
+	for i, dbUser := range dbUsers {
+		users[i] = r.dbToEntityUser(dbUser)
+	}
+
+	return users, nil
+}
+
+// FindAll finds one page of users in ID order, the cursor is the last ID of the page
+func (r UserRepository) FindAll(ctx context.Context, page entity.Page) ([]*entity.User, string, error) {
+	after, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := r.getDB(ctx)
+	if after != nil {
+		query = query.Where("id > ?", string(after))
+	}
+
+	var dbUsers []model_db.User
+	if err := query.Order("id").Limit(page.Limit).Find(&dbUsers).Error; err != nil {
+		return nil, "", err
+	}
+
+	users := make([]*entity.User, len(dbUsers))
+	for i, dbUser := range dbUsers {
+		users[i] = r.dbToEntityUser(dbUser)
+	}
+
+	nextCursor := ""
+	if len(dbUsers) == page.Limit {
+		nextCursor = encodeCursor([]byte(dbUsers[len(dbUsers)-1].ID.String()))
+	}
+
+	return users, nextCursor, nil
+}
+
 func (r UserRepository) Update(ctx context.Context, existingUser entity.User, updatedUser entity.User) (*entity.User, error) {
 	// Patch changes in updatedUser to existingUser
 	user := existingUser
@@ -134,7 +185,7 @@ func (r UserRepository) Update(ctx context.Context, existingUser entity.User, up
 	dbUser := r.entityToDBUser(user)
 
 	if err := r.getDB(ctx).Model(&model_db.User{}).Where("id = ?", user.ID).Updates(dbUser).Error; err != nil {
-		return nil, err
+		return nil, conflict(err)
 	}
 
 	return r.dbToEntityUser(dbUser), nil
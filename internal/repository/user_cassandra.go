@@ -2,29 +2,242 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/google/uuid"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
 	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
 )
 
+// usernameClaimGracePeriod is how old a claim without a user row must be to be orphaned
+const usernameClaimGracePeriod = time.Minute
+
 type UserRepositoryNoSQL struct {
-	db *gocql.Session
+	db  NoSQLSession
+	log *logrus.Logger
 }
 
-func NewUserRepositoryNoSQL(db *gocql.Session) UserRepositoryNoSQL {
+func NewUserRepositoryNoSQL(db NoSQLSession, log *logrus.Logger) UserRepositoryNoSQL {
 	return UserRepositoryNoSQL{
-		db: db,
+		db:  db,
+		log: log,
 	}
 }
 
-// Create creates a new blog
+// Create creates a new user and its username lookup row
 func (r UserRepositoryNoSQL) Create(ctx context.Context, userEntity entity.User) (*entity.User, error) {
-	// Create blog in Cassandra
-	userId, _ := gocql.ParseUUID(userEntity.ID.String())
+	// Create user in Cassandra
+	userId := gocql.UUID(userEntity.ID)
+
+	now := time.Now()
+	if userEntity.CreatedAt.IsZero() {
+		userEntity.CreatedAt = now
+	}
+	if userEntity.UpdatedAt.IsZero() {
+		userEntity.UpdatedAt = now
+	}
 
-	if err := r.db.Query(`INSERT INTO users (id, name, username) VALUES (?, ?, ?)`, userId, userEntity.Name, userEntity.Username).Exec(); err != nil {
+	// the claim is an LWT, it runs right away and is released if the unit of work rolls back
+	if err := r.claimUsername(ctx, userEntity.Username, userId); err != nil {
+		return nil, err
+	}
+
+	if err := execNoSQL(ctx, r.db, idempotent, `INSERT INTO users (id, name, username, password, token, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		userId, userEntity.Name, userEntity.Username, userEntity.Password, userEntity.Token, userEntity.CreatedAt, userEntity.UpdatedAt); err != nil {
 		return nil, err
 	}
 
 	return &userEntity, nil
 }
+
+// claimUsername takes the users_by_username row with an LWT, retrying once over an orphaned claim
+func (r UserRepositoryNoSQL) claimUsername(ctx context.Context, username string, userId gocql.UUID) error {
+	for attempt := 0; attempt < 2; attempt++ {
+		existing := map[string]interface{}{}
+		applied, err := queryNoSQL(ctx, r.db, notIdempotent, `INSERT INTO users_by_username (username, id, claimed_at) VALUES (?, ?, ?) IF NOT EXISTS`,
+			username, userId, time.Now()).MapScanCASContext(ctx, existing)
+		if err != nil {
+			return err
+		}
+		if applied {
+			if tx := context_db.GetBatch(ctx); tx != nil {
+				tx.Compensate(`DELETE FROM users_by_username WHERE username = ? IF id = ?`, username, userId)
+			}
+			return nil
+		}
+		existingId, _ := existing["id"].(gocql.UUID)
+		existingClaimedAt, _ := existing["claimed_at"].(time.Time)
+		if existingId == userId {
+			return nil
+		}
+
+		orphaned, err := r.orphanedClaim(ctx, username, existingId, existingClaimedAt)
+		if err != nil {
+			return err
+		}
+		if !orphaned {
+			return entity.ErrConflict
+		}
+		r.log.Warnf("Removing orphaned claim of username %s by %s", username, existingId)
+		if _, err := queryNoSQL(ctx, r.db, notIdempotent, `DELETE FROM users_by_username WHERE username = ? IF id = ?`, username, existingId).
+			MapScanCASContext(ctx, map[string]interface{}{}); err != nil {
+			return err
+		}
+	}
+
+	return entity.ErrConflict
+}
+
+// orphanedClaim reports an old claim whose user row never landed or moved to another username
+func (r UserRepositoryNoSQL) orphanedClaim(ctx context.Context, username string, userId gocql.UUID, claimedAt time.Time) (bool, error) {
+	if time.Since(claimedAt) < usernameClaimGracePeriod {
+		return false, nil
+	}
+
+	var currentUsername string
+	err := queryNoSQL(ctx, r.db, idempotent, `SELECT username FROM users WHERE id = ?`, userId).ScanContext(ctx, &currentUsername)
+	switch {
+	case errors.Is(notFound(err), entity.ErrNotFound):
+		return true, nil
+	case err != nil:
+		return false, err
+	default:
+		return currentUsername != username, nil
+	}
+}
+
+// FindById finds a user by ID
+func (r UserRepositoryNoSQL) FindById(ctx context.Context, userID string) (*entity.User, error) {
+	userId, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		user      entity.User
+		id        gocql.UUID
+		createdAt time.Time
+		updatedAt time.Time
+	)
+	if err := queryNoSQL(ctx, r.db, idempotent, `SELECT id, name, username, password, token, created_at, updated_at FROM users WHERE id = ?`, userId).
+		ScanContext(ctx, &id, &user.Name, &user.Username, &user.Password, &user.Token, &createdAt, &updatedAt); err != nil {
+		return nil, notFound(err)
+	}
+	user.ID = uuid.UUID(id)
+	user.CreatedAt = createdAt
+	user.UpdatedAt = updatedAt
+
+	return &user, nil
+}
+
+// FindAll finds one page of users in token order, resuming from the driver paging state
+func (r UserRepositoryNoSQL) FindAll(ctx context.Context, page entity.Page) ([]*entity.User, string, error) {
+	pageState, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	iter := queryNoSQL(ctx, r.db, idempotent, `SELECT id, name, username, password, token, created_at, updated_at FROM users`).
+		PageSize(page.Limit).
+		PageState(pageState).
+		IterContext(ctx)
+	nextPageState := iter.PageState()
+
+	var (
+		users     []*entity.User
+		id        gocql.UUID
+		name      string
+		username  string
+		password  string
+		token     string
+		createdAt time.Time
+		updatedAt time.Time
+	)
+	for iter.Scan(&id, &name, &username, &password, &token, &createdAt, &updatedAt) {
+		users = append(users, &entity.User{
+			ID:        uuid.UUID(id),
+			Name:      name,
+			Username:  username,
+			Password:  password,
+			Token:     token,
+			CreatedAt: createdAt,
+			UpdatedAt: updatedAt,
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return users, encodeCursor(nextPageState), nil
+}
+
+// FindByUsername finds a user by username through the users_by_username lookup table
+func (r UserRepositoryNoSQL) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
+	var userId gocql.UUID
+	if err := queryNoSQL(ctx, r.db, idempotent, `SELECT id FROM users_by_username WHERE username = ?`, username).ScanContext(ctx, &userId); err != nil {
+		return nil, notFound(err)
+	}
+
+	return r.FindById(ctx, userId.String())
+}
+
+func (r UserRepositoryNoSQL) Update(ctx context.Context, existingUser entity.User, updatedUser entity.User) (*entity.User, error) {
+	// Patch changes in updatedUser to existingUser
+	user := existingUser
+
+	if updatedUser.Name != "" {
+		user.Name = updatedUser.Name
+	}
+	if updatedUser.Username != "" {
+		user.Username = updatedUser.Username
+	}
+	if updatedUser.Password != "" {
+		user.Password = updatedUser.Password
+	}
+	if updatedUser.Token != "" {
+		user.Token = updatedUser.Token
+	}
+	// a copied update keeps the time of the primary store
+	user.UpdatedAt = updatedUser.UpdatedAt
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = time.Now()
+	}
+
+	userId := gocql.UUID(user.ID)
+
+	if user.Username != existingUser.Username {
+		if err := r.claimUsername(ctx, user.Username, userId); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := execNoSQL(ctx, r.db, idempotent, `UPDATE users SET name = ?, username = ?, password = ?, token = ?, updated_at = ? WHERE id = ?`,
+		user.Name, user.Username, user.Password, user.Token, user.UpdatedAt, userId); err != nil {
+		return nil, err
+	}
+
+	if user.Username != existingUser.Username {
+		if err := r.releaseUsername(ctx, existingUser.Username, userId); err != nil {
+			return nil, err
+		}
+	}
+
+	return &user, nil
+}
+
+// releaseUsername deletes the user's claim with an LWT, claimed again if the unit of work rolls back
+func (r UserRepositoryNoSQL) releaseUsername(ctx context.Context, username string, userId gocql.UUID) error {
+	applied, err := queryNoSQL(ctx, r.db, notIdempotent, `DELETE FROM users_by_username WHERE username = ? IF id = ?`, username, userId).
+		MapScanCASContext(ctx, map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+
+	if tx := context_db.GetBatch(ctx); tx != nil && applied {
+		tx.Compensate(`INSERT INTO users_by_username (username, id, claimed_at) VALUES (?, ?, ?) IF NOT EXISTS`, username, userId, time.Now())
+	}
+	return nil
+}
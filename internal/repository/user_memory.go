@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	context_db "github.com/rifkiadrn/cassandra-explore/internal/context/db"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// UserRepositoryMemory keeps users in process
+type UserRepositoryMemory struct {
+	store     *context_db.MemoryStore
+	users     map[string]entity.User
+	usernames map[string]string // username to user ID, usernames are unique
+}
+
+func NewUserRepositoryMemory(store *context_db.MemoryStore) UserRepositoryMemory {
+	return UserRepositoryMemory{
+		store:     store,
+		users:     map[string]entity.User{},
+		usernames: map[string]string{},
+	}
+}
+
+// Create creates a new user, a taken ID or username is entity.ErrConflict
+func (r UserRepositoryMemory) Create(ctx context.Context, user entity.User) (*entity.User, error) {
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	now := time.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = now
+	}
+
+	err := r.store.Write(ctx, func(undo func(func())) error {
+		id := user.ID.String()
+		if _, ok := r.users[id]; ok {
+			return entity.ErrConflict
+		}
+		if _, ok := r.usernames[user.Username]; ok {
+			return entity.ErrConflict
+		}
+
+		r.users[id] = user
+		r.usernames[user.Username] = id
+		undo(func() {
+			delete(r.users, id)
+			delete(r.usernames, user.Username)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// FindById finds a user by ID
+func (r UserRepositoryMemory) FindById(ctx context.Context, userID string) (*entity.User, error) {
+	var (
+		user entity.User
+		ok   bool
+	)
+	r.store.Read(ctx, func() {
+		user, ok = r.users[userID]
+	})
+	if !ok {
+		return nil, entity.ErrNotFound
+	}
+
+	return &user, nil
+}
+
+// FindByUsername finds a user by username
+func (r UserRepositoryMemory) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
+	var (
+		user entity.User
+		ok   bool
+	)
+	r.store.Read(ctx, func() {
+		if id, found := r.usernames[username]; found {
+			user, ok = r.users[id]
+		}
+	})
+	if !ok {
+		return nil, entity.ErrNotFound
+	}
+
+	return &user, nil
+}
+
+// Update patches the non-empty fields of updatedUser onto the stored user
+func (r UserRepositoryMemory) Update(ctx context.Context, existingUser entity.User, updatedUser entity.User) (*entity.User, error) {
+	var user entity.User
+	err := r.store.Write(ctx, func(undo func(func())) error {
+		id := existingUser.ID.String()
+		stored, ok := r.users[id]
+		if !ok {
+			return entity.ErrNotFound
+		}
+
+		user = stored
+		if updatedUser.Name != "" {
+			user.Name = updatedUser.Name
+		}
+		if updatedUser.Username != "" {
+			user.Username = updatedUser.Username
+		}
+		if updatedUser.Password != "" {
+			user.Password = updatedUser.Password
+		}
+		if updatedUser.Token != "" {
+			user.Token = updatedUser.Token
+		}
+		user.UpdatedAt = time.Now()
+
+		if owner, taken := r.usernames[user.Username]; taken && owner != id {
+			return entity.ErrConflict
+		}
+
+		r.users[id] = user
+		delete(r.usernames, stored.Username)
+		r.usernames[user.Username] = id
+		undo(func() {
+			r.users[id] = stored
+			delete(r.usernames, user.Username)
+			r.usernames[stored.Username] = id
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// FindBatch finds up to limit users with an ID greater than afterID, in ID order
+func (r UserRepositoryMemory) FindBatch(ctx context.Context, afterID string, limit int) ([]*entity.User, error) {
+	var users []*entity.User
+	r.store.Read(ctx, func() {
+		users = r.sorted(afterID)
+	})
+	if len(users) > limit {
+		users = users[:limit]
+	}
+
+	return users, nil
+}
+
+// FindAll finds one page of users in ID order, the cursor is the last ID of the page
+func (r UserRepositoryMemory) FindAll(ctx context.Context, page entity.Page) ([]*entity.User, string, error) {
+	after, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	users, err := r.FindBatch(ctx, string(after), page.Limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(users) == page.Limit && len(users) > 0 {
+		nextCursor = encodeCursor([]byte(users[len(users)-1].ID.String()))
+	}
+
+	return users, nextCursor, nil
+}
+
+// sorted copies the users with an ID greater than afterID in ID order, the caller holds the store
+func (r UserRepositoryMemory) sorted(afterID string) []*entity.User {
+	var users []*entity.User
+	for id, user := range r.users {
+		if id > afterID {
+			user := user
+			users = append(users, &user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID.String() < users[j].ID.String()
+	})
+	return users
+}
@@ -0,0 +1,151 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	authContext "github.com/rifkiadrn/cassandra-explore/internal/handler/rest/context"
+	"github.com/sirupsen/logrus"
+)
+
+// CassandraObserver records every gocql query and batch execution
+type CassandraObserver struct {
+	recorder *Recorder
+	log      *logrus.Logger
+}
+
+func NewCassandraObserver(recorder *Recorder, log *logrus.Logger) *CassandraObserver {
+	return &CassandraObserver{recorder: recorder, log: log}
+}
+
+func (o *CassandraObserver) ObserveQuery(ctx context.Context, q gocql.ObservedQuery) {
+	var consistency gocql.Consistency
+	if q.Query != nil {
+		consistency = q.Query.GetConsistency()
+	}
+	o.observe(ctx, StatementName(q.Statement), q.Host, consistency, q.Attempt, q.Err, q.End.Sub(q.Start))
+}
+
+func (o *CassandraObserver) ObserveBatch(ctx context.Context, b gocql.ObservedBatch) {
+	var consistency gocql.Consistency
+	if b.Batch != nil {
+		consistency = b.Batch.GetConsistency()
+	}
+	o.observe(ctx, batchName(b.Statements), b.Host, consistency, b.Attempt, b.Err, b.End.Sub(b.Start))
+}
+
+func (o *CassandraObserver) observe(ctx context.Context, statement string, host *gocql.HostInfo, consistency gocql.Consistency,
+	attempt int, err error, latency time.Duration) {
+	labels := Labels{
+		Store:       StoreCassandra,
+		Statement:   statement,
+		Consistency: consistency.String(),
+		Attempt:     attempt,
+		Error:       cassandraErrorClass(err),
+	}
+	if host != nil {
+		labels.Host = host.ConnectAddress().String()
+	}
+	o.recorder.Observe(labels, latency)
+
+	o.log.WithFields(logrus.Fields{
+		"request_id":  authContext.GetRequestIDFromContext(ctx),
+		"host":        labels.Host,
+		"consistency": labels.Consistency,
+		"attempt":     attempt,
+		"latency":     latency,
+		"error":       err,
+	}).Debugf("CQL %s", statement)
+}
+
+// batchName names a batch by its distinct statements in order
+func batchName(statements []string) string {
+	var names []string
+	seen := map[string]bool{}
+	for _, stmt := range statements {
+		name := StatementName(stmt)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return "batch " + strings.Join(names, ", ")
+}
+
+func cassandraErrorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+	if errors.Is(err, gocql.ErrTimeoutNoResponse) || errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	if errors.Is(err, context.Canceled) {
+		return "canceled"
+	}
+	var requestErr gocql.RequestError
+	if errors.As(err, &requestErr) {
+		// e.g. RequestErrWriteTimeout or RequestErrUnavailable
+		return strings.TrimPrefix(fmt.Sprintf("%T", requestErr), "*gocql.")
+	}
+	return "error"
+}
+
+// traceDelay gives the replicas time to write their trace events
+const traceDelay = 500 * time.Millisecond
+
+// TraceLogger is a gocql.Tracer logging the server side trace of each query
+type TraceLogger struct {
+	session   *gocql.Session
+	log       *logrus.Logger
+	requestID string
+}
+
+func NewTraceLogger(session *gocql.Session, log *logrus.Logger, requestID string) *TraceLogger {
+	return &TraceLogger{session: session, log: log, requestID: requestID}
+}
+
+// Trace reads the trace session and events in the background, the query has already answered
+func (t *TraceLogger) Trace(traceId []byte) {
+	go func() {
+		time.Sleep(traceDelay)
+		if err := t.logTrace(traceId); err != nil {
+			t.log.Warnf("Failed read trace %x of request %s : %+v", traceId, t.requestID, err)
+		}
+	}()
+}
+
+func (t *TraceLogger) logTrace(traceId []byte) error {
+	ctx := context.Background()
+	log := t.log.WithFields(logrus.Fields{
+		"request_id": t.requestID,
+		"trace_id":   fmt.Sprintf("%x", traceId),
+	})
+
+	var (
+		coordinator string
+		request     string
+		duration    int
+	)
+	if err := t.session.Query(`SELECT coordinator, request, duration FROM system_traces.sessions WHERE session_id = ?`, traceId).
+		Consistency(gocql.One).Idempotent(true).ScanContext(ctx, &coordinator, &request, &duration); err != nil {
+		return err
+	}
+	log.Infof("CQL trace %s on coordinator %s took %s", request, coordinator, time.Duration(duration)*time.Microsecond)
+
+	iter := t.session.Query(`SELECT activity, source, source_elapsed, thread FROM system_traces.events WHERE session_id = ?`, traceId).
+		Consistency(gocql.One).Idempotent(true).IterContext(ctx)
+	var (
+		activity string
+		source   string
+		elapsed  int
+		thread   string
+	)
+	for iter.Scan(&activity, &source, &elapsed, &thread) {
+		log.Infof("CQL trace event %s [%s] on %s after %s", activity, thread, source, time.Duration(elapsed)*time.Microsecond)
+	}
+	return iter.Close()
+}
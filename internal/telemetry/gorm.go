@@ -0,0 +1,104 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	authContext "github.com/rifkiadrn/cassandra-explore/internal/handler/rest/context"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const gormStartKey = "telemetry:start"
+
+// GormPlugin records every GORM create, query, update, delete, row and raw statement
+type GormPlugin struct {
+	recorder *Recorder
+	log      *logrus.Logger
+	host     string // GORM does not report the server, every statement goes to the configured host
+}
+
+func NewGormPlugin(recorder *Recorder, log *logrus.Logger, host string) *GormPlugin {
+	return &GormPlugin{recorder: recorder, log: log, host: host}
+}
+
+func (p *GormPlugin) Name() string {
+	return "telemetry"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	return errors.Join(
+		callback.Create().Before("gorm:create").Register("telemetry:before_create", p.before),
+		callback.Create().After("gorm:create").Register("telemetry:after_create", p.after("create")),
+		callback.Query().Before("gorm:query").Register("telemetry:before_query", p.before),
+		callback.Query().After("gorm:query").Register("telemetry:after_query", p.after("query")),
+		callback.Update().Before("gorm:update").Register("telemetry:before_update", p.before),
+		callback.Update().After("gorm:update").Register("telemetry:after_update", p.after("update")),
+		callback.Delete().Before("gorm:delete").Register("telemetry:before_delete", p.before),
+		callback.Delete().After("gorm:delete").Register("telemetry:after_delete", p.after("delete")),
+		callback.Row().Before("gorm:row").Register("telemetry:before_row", p.before),
+		callback.Row().After("gorm:row").Register("telemetry:after_row", p.after("row")),
+		callback.Raw().Before("gorm:raw").Register("telemetry:before_raw", p.before),
+		callback.Raw().After("gorm:raw").Register("telemetry:after_raw", p.after("raw")),
+	)
+}
+
+func (p *GormPlugin) before(db *gorm.DB) {
+	db.InstanceSet(gormStartKey, time.Now())
+}
+
+func (p *GormPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(gormStartKey)
+		if !ok {
+			return
+		}
+		latency := time.Since(value.(time.Time))
+
+		statement := operation + " " + db.Statement.Table
+		if db.Statement.Table == "" {
+			statement = StatementName(db.Statement.SQL.String())
+		}
+
+		labels := Labels{
+			Store:     StorePostgres,
+			Statement: statement,
+			Host:      p.host,
+			Error:     postgresErrorClass(db.Error),
+		}
+		p.recorder.Observe(labels, latency)
+
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		p.log.WithFields(logrus.Fields{
+			"request_id": authContext.GetRequestIDFromContext(ctx),
+			"host":       p.host,
+			"rows":       db.RowsAffected,
+			"latency":    latency,
+			"error":      db.Error,
+		}).Debugf("SQL %s", statement)
+	}
+}
+
+func postgresErrorClass(err error) string {
+	switch {
+	case err == nil, errors.Is(err, gorm.ErrRecordNotFound):
+		return ""
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return "duplicated_key"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	}
+	// pgconn.PgError, without depending on the driver
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		return "sqlstate_" + pgErr.SQLState()
+	}
+	return "error"
+}
@@ -0,0 +1,139 @@
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stores of the store label
+const (
+	StoreCassandra = "cassandra"
+	StorePostgres  = "postgres"
+)
+
+// latencyBuckets are the histogram upper bounds in seconds
+var latencyBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Labels identify one latency series
+type Labels struct {
+	Store       string
+	Statement   string // Operation and table, e.g. "select users_by_username"
+	Host        string
+	Consistency string // Empty for Postgres
+	Attempt     int    // 0 for the first execution, retries and speculative executions count up
+	Error       string // Empty on success, otherwise a short error class
+}
+
+type histogram struct {
+	counts []uint64 // Per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// Recorder keeps a latency histogram per statement, host, attempt, consistency and error
+type Recorder struct {
+	mu     sync.Mutex
+	series map[Labels]*histogram
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{series: map[Labels]*histogram{}}
+}
+
+func (r *Recorder) Observe(labels Labels, latency time.Duration) {
+	seconds := latency.Seconds()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, ok := r.series[labels]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(latencyBuckets)+1)}
+		r.series[labels] = h
+	}
+	h.counts[sort.SearchFloat64s(latencyBuckets, seconds)]++
+	h.count++
+	h.sum += seconds
+}
+
+// WritePrometheus writes every series in the Prometheus text format
+func (r *Recorder) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	labels := make([]Labels, 0, len(r.series))
+	snapshot := make(map[Labels]histogram, len(r.series))
+	for l, h := range r.series {
+		labels = append(labels, l)
+		snapshot[l] = histogram{counts: append([]uint64(nil), h.counts...), count: h.count, sum: h.sum}
+	}
+	r.mu.Unlock()
+
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].String() < labels[j].String()
+	})
+
+	var b strings.Builder
+	b.WriteString("# HELP db_statement_duration_seconds Latency of Cassandra and Postgres statements.\n")
+	b.WriteString("# TYPE db_statement_duration_seconds histogram\n")
+	for _, l := range labels {
+		h := snapshot[l]
+		var cumulative uint64
+		for i, bound := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "db_statement_duration_seconds_bucket{%s,le=\"%s\"} %d\n", l, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(&b, "db_statement_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", l, h.count)
+		fmt.Fprintf(&b, "db_statement_duration_seconds_sum{%s} %g\n", l, h.sum)
+		fmt.Fprintf(&b, "db_statement_duration_seconds_count{%s} %d\n", l, h.count)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// String formats the labels as a Prometheus label set without braces
+func (l Labels) String() string {
+	return fmt.Sprintf(`store=%q,statement=%q,host=%q,consistency=%q,attempt="%d",error=%q`,
+		l.Store, l.Statement, l.Host, l.Consistency, l.Attempt, l.Error)
+}
+
+// StatementName names a statement by its operation and table, e.g. "insert blogs_by_author_bucket"
+func StatementName(stmt string) string {
+	fields := strings.Fields(stmt)
+	if len(fields) == 0 {
+		return "unknown"
+	}
+
+	operation := strings.ToLower(fields[0])
+	var marker string
+	switch operation {
+	case "select", "delete":
+		marker = "from"
+	case "insert":
+		marker = "into"
+	case "update":
+		if len(fields) > 1 {
+			return operation + " " + table(fields[1])
+		}
+		return operation
+	default:
+		return operation
+	}
+
+	for i, field := range fields[:len(fields)-1] {
+		if strings.EqualFold(field, marker) {
+			return operation + " " + table(fields[i+1])
+		}
+	}
+	return operation
+}
+
+// table strips quotes and the column list from a table reference
+func table(ref string) string {
+	ref, _, _ = strings.Cut(ref, "(")
+	return strings.ToLower(strings.ReplaceAll(ref, `"`, ""))
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+type IUserBatchRepo interface {
+	FindBatch(ctx context.Context, afterID string, limit int) ([]*entity.User, error)
+}
+
+type IBlogBatchRepo interface {
+	FindBatch(ctx context.Context, afterID string, limit int) ([]*entity.Blog, error)
+}
+
+// IBlogCopyRepo writes blogs copied from another store, overwriting a copy written before
+type IBlogCopyRepo interface {
+	Copy(ctx context.Context, blog entity.Blog) error
+}
+
+type ICheckpointStore interface {
+	Load(ctx context.Context, checkpoint interface{}) (bool, error)
+	Save(ctx context.Context, checkpoint interface{}) error
+}
+
+type BackfillConfig struct {
+	BatchSize   int
+	Concurrency int
+	DryRun      bool
+	Users       bool
+	Blogs       bool
+}
+
+// BackfillUseCase copies existing Postgres users and blogs into Cassandra
+type BackfillUseCase struct {
+	log                 *logrus.Logger
+	userRepository      IUserBatchRepo
+	blogRepository      IBlogBatchRepo
+	userRepositoryNoSQL IUserRepoNoSQL
+	blogRepositoryNoSQL IBlogCopyRepo
+	checkpointStore     ICheckpointStore
+	config              BackfillConfig
+}
+
+func NewBackfillUseCase(logger *logrus.Logger, userRepository IUserBatchRepo, blogRepository IBlogBatchRepo,
+	userRepositoryNoSQL IUserRepoNoSQL, blogRepositoryNoSQL IBlogCopyRepo, checkpointStore ICheckpointStore, config BackfillConfig) BackfillUseCase {
+	return BackfillUseCase{
+		log:                 logger,
+		userRepository:      userRepository,
+		blogRepository:      blogRepository,
+		userRepositoryNoSQL: userRepositoryNoSQL,
+		blogRepositoryNoSQL: blogRepositoryNoSQL,
+		checkpointStore:     checkpointStore,
+		config:              config,
+	}
+}
+
+// Run copies users then blogs batch by batch, saving a checkpoint after every written batch
+func (b BackfillUseCase) Run(ctx context.Context) (entity.BackfillReport, error) {
+	report := entity.BackfillReport{DryRun: b.config.DryRun}
+
+	if _, err := b.checkpointStore.Load(ctx, &report.Checkpoint); err != nil {
+		return report, err
+	}
+
+	if b.config.Users && !report.Checkpoint.UsersDone {
+		if err := b.backfillUsers(ctx, &report); err != nil {
+			return report, err
+		}
+	}
+
+	if b.config.Blogs && !report.Checkpoint.BlogsDone {
+		if err := b.backfillBlogs(ctx, &report); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+func (b BackfillUseCase) backfillUsers(ctx context.Context, report *entity.BackfillReport) error {
+	for {
+		users, err := b.userRepository.FindBatch(ctx, report.Checkpoint.UsersAfter, b.config.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			report.Checkpoint.UsersDone = true
+			return b.saveCheckpoint(ctx, report.Checkpoint)
+		}
+
+		if !b.config.DryRun {
+			group, groupCtx := errgroup.WithContext(ctx)
+			group.SetLimit(b.config.Concurrency)
+			for _, user := range users {
+				user := user
+				group.Go(func() error {
+					_, err := b.userRepositoryNoSQL.Create(groupCtx, *user)
+					return err
+				})
+			}
+			if err := group.Wait(); err != nil {
+				return err
+			}
+		}
+
+		report.Users += len(users)
+		report.Checkpoint.UsersAfter = users[len(users)-1].ID.String()
+		if err := b.saveCheckpoint(ctx, report.Checkpoint); err != nil {
+			return err
+		}
+		b.log.Infof("Backfilled %d users, last id %s", report.Users, report.Checkpoint.UsersAfter)
+	}
+}
+
+func (b BackfillUseCase) backfillBlogs(ctx context.Context, report *entity.BackfillReport) error {
+	for {
+		blogs, err := b.blogRepository.FindBatch(ctx, report.Checkpoint.BlogsAfter, b.config.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(blogs) == 0 {
+			report.Checkpoint.BlogsDone = true
+			return b.saveCheckpoint(ctx, report.Checkpoint)
+		}
+
+		if !b.config.DryRun {
+			group, groupCtx := errgroup.WithContext(ctx)
+			group.SetLimit(b.config.Concurrency)
+			for _, blog := range blogs {
+				blog := blog
+				group.Go(func() error {
+					return b.blogRepositoryNoSQL.Copy(groupCtx, *blog)
+				})
+			}
+			if err := group.Wait(); err != nil {
+				return err
+			}
+		}
+
+		report.Blogs += len(blogs)
+		report.Checkpoint.BlogsAfter = blogs[len(blogs)-1].ID.String()
+		if err := b.saveCheckpoint(ctx, report.Checkpoint); err != nil {
+			return err
+		}
+		b.log.Infof("Backfilled %d blogs, last id %s", report.Blogs, report.Checkpoint.BlogsAfter)
+	}
+}
+
+// saveCheckpoint persists progress, a dry run never moves the real checkpoint
+func (b BackfillUseCase) saveCheckpoint(ctx context.Context, checkpoint entity.BackfillCheckpoint) error {
+	if b.config.DryRun {
+		return nil
+	}
+	return b.checkpointStore.Save(ctx, checkpoint)
+}
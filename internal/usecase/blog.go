@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	gocql "github.com/apache/cassandra-gocql-driver/v2"
 	"github.com/go-playground/validator/v10"
@@ -14,26 +16,64 @@ import (
 
 type IBlog interface {
 	Create(ctx context.Context, blog entity.Blog) (*entity.Blog, error)
-	FindAll(ctx context.Context, userID string) ([]*entity.Blog, error)
+	FindAll(ctx context.Context, userID string, page entity.Page) ([]*entity.Blog, string, error)
+	FindBefore(ctx context.Context, userID string, before entity.FeedPosition, limit int) ([]*entity.Blog, error)
+	FindById(ctx context.Context, blogID string) (*entity.Blog, error)
+	Update(ctx context.Context, blog entity.Blog) (*entity.Blog, error)
+	Delete(ctx context.Context, blogID string) error
 }
 
+// IBlogPublisher is told about every created, updated and deleted blog
+type IBlogPublisher interface {
+	Publish(blog entity.Blog)
+	Retract(blog entity.Blog)
+}
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
 type BlogUseCase struct {
-	uow            UnitOfWork
-	log            *logrus.Logger
-	validate       *validator.Validate
-	blogRepository IBlog
+	uow                  UnitOfWork
+	log                  *logrus.Logger
+	validate             *validator.Validate
+	blogRepository       IBlog
+	blogCountRepository  IBlogCountRepo
+	revisionRepository   IBlogRevisionRepo
+	publisher            IBlogPublisher
+	blogReadRepository   IBlog // Serves GetBlogs, the primary store of shadow reads
+	blogShadowRepository IBlog
+	shadowReader         *ShadowReader
+	searchRepository     IBlogSearchRepo       // nil when search is not set up
+	searchIndexRebuilder *SearchIndexRebuilder // nil when the search repository needs no rebuild
+	followRepository     IFollowRepo
+	tagRepository        IBlogTagRepo // nil when blogs are not filed under their tags
 }
 
+// NewBlogUseCase creates the blog use case, publisher is nil when created blogs go nowhere else
 func NewBlogUseCase(uow UnitOfWork, logger *logrus.Logger, validate *validator.Validate,
-	blogRepository IBlog) BlogUseCase {
+	blogRepository IBlog, blogCountRepository IBlogCountRepo, revisionRepository IBlogRevisionRepo, publisher IBlogPublisher) BlogUseCase {
 	return BlogUseCase{
-		uow:            uow,
-		log:            logger,
-		validate:       validate,
-		blogRepository: blogRepository,
+		uow:                 uow,
+		log:                 logger,
+		validate:            validate,
+		blogRepository:      blogRepository,
+		blogCountRepository: blogCountRepository,
+		revisionRepository:  revisionRepository,
+		publisher:           publisher,
+		blogReadRepository:  blogRepository,
 	}
 }
 
+// WithShadowReads serves GetBlogs from primary and repeats a sample of first pages on secondary
+func (b BlogUseCase) WithShadowReads(primary IBlog, secondary IBlog, shadowReader *ShadowReader) BlogUseCase {
+	b.blogReadRepository = primary
+	b.blogShadowRepository = secondary
+	b.shadowReader = shadowReader
+	return b
+}
+
 func (b BlogUseCase) CreateBlog(ctx context.Context, request entity.Blog) (entity.Blog, error) {
 	// Get authenticated user
 	user, err := authContext.GetUserFromContext(ctx)
@@ -45,11 +85,13 @@ func (b BlogUseCase) CreateBlog(ctx context.Context, request entity.Blog) (entit
 
 	// Create domain entity
 	blogEntity := entity.Blog{
-		ID:       uuid.New(),
-		AuthorID: user.ID,
-		Username: user.Username,
-		Content:  request.Content,
-		Ts:       time.Time(),
+		ID:        uuid.New(),
+		AuthorID:  user.ID,
+		Username:  user.Username,
+		Content:   request.Content,
+		Ts:        time.Time(),
+		ExpiresAt: request.ExpiresAt,
+		Revision:  1,
 	}
 
 	// Validate request
@@ -58,25 +100,72 @@ func (b BlogUseCase) CreateBlog(ctx context.Context, request entity.Blog) (entit
 		return entity.Blog{}, fiber.ErrBadRequest
 	}
 
-	res, err := b.blogRepository.Create(ctx, blogEntity)
+	// Cassandra counters and dual copies are written once the transaction commits
+	tx, txCtx, err := b.uow.Begin(ctx)
 	if err != nil {
 		return entity.Blog{}, err
 	}
+	defer tx.Rollback()
+
+	res, err := b.blogRepository.Create(txCtx, blogEntity)
+	if err != nil {
+		return entity.Blog{}, err
+	}
+
+	if err := b.blogCountRepository.Increment(txCtx, user.ID.String(), 1); err != nil {
+		return entity.Blog{}, err
+	}
+
+	if b.tagRepository != nil {
+		if err := b.tagRepository.SetTags(txCtx, *res, nil); err != nil {
+			return entity.Blog{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.Blog{}, err
+	}
+
+	if b.publisher != nil {
+		b.publisher.Publish(*res)
+	}
 
 	return *res, nil
 }
 
-func (b BlogUseCase) GetBlogs(ctx context.Context) ([]entity.Blog, error) {
+func (b BlogUseCase) GetBlogs(ctx context.Context, page entity.Page) ([]entity.Blog, string, error) {
 	// Get authenticated user
 	user, err := authContext.GetUserFromContext(ctx)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	if page.Limit == 0 {
+		page.Limit = DefaultPageLimit
+	}
+	if page.Limit < 0 || page.Limit > MaxPageLimit {
+		return nil, "", fiber.ErrBadRequest
 	}
 
 	// Get blogs via repository
-	blogs, err := b.blogRepository.FindAll(ctx, user.ID.String())
+	blogs, nextCursor, err := b.blogReadRepository.FindAll(ctx, user.ID.String(), page)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, entity.ErrInvalidCursor) {
+			b.log.Warnf("Invalid cursor : %+v", err)
+			return nil, "", fiber.ErrBadRequest
+		}
+		return nil, "", err
+	}
+
+	if b.shadowReader != nil && page.Cursor == "" {
+		userID := user.ID.String()
+		b.shadowReader.Compare("blogs", userID, func(ctx context.Context) ([]string, error) {
+			shadow, _, err := b.blogShadowRepository.FindAll(ctx, userID, page)
+			if err != nil {
+				return nil, err
+			}
+			return blogPageDiffFields(ctx, blogs, shadow, b.blogReadRepository, b.blogShadowRepository)
+		})
 	}
 
 	// Dereference pointers to return values
@@ -85,5 +174,161 @@ func (b BlogUseCase) GetBlogs(ctx context.Context) ([]entity.Blog, error) {
 		result[i] = *blog
 	}
 
-	return result, nil
+	return result, nextCursor, nil
+}
+
+// GetBlog returns one of the authenticated user's blogs
+func (b BlogUseCase) GetBlog(ctx context.Context, blogID string) (entity.Blog, error) {
+	blog, err := b.findOwnBlog(ctx, b.blogReadRepository, blogID)
+	if err != nil {
+		return entity.Blog{}, err
+	}
+
+	if b.shadowReader != nil {
+		b.shadowReader.Compare("blog", blogID, func(ctx context.Context) ([]string, error) {
+			shadow, err := b.blogShadowRepository.FindById(ctx, blogID)
+			if errors.Is(err, entity.ErrNotFound) {
+				return []string{"missing"}, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return blogDiffFields(blog, *shadow), nil
+		})
+	}
+
+	return blog, nil
+}
+
+// UpdateBlog replaces the content of one of the authenticated user's blogs, keeping the old one as a revision
+func (b BlogUseCase) UpdateBlog(ctx context.Context, blogID string, request entity.Blog) (entity.Blog, error) {
+	if err := b.validate.Struct(request); err != nil {
+		b.log.Warnf("Invalid request body : %+v", err)
+		return entity.Blog{}, fiber.ErrBadRequest
+	}
+
+	blog, err := b.findOwnBlog(ctx, b.blogRepository, blogID)
+	if err != nil {
+		return entity.Blog{}, err
+	}
+
+	tx, txCtx, err := b.uow.Begin(ctx)
+	if err != nil {
+		return entity.Blog{}, err
+	}
+	defer tx.Rollback()
+
+	revision := entity.BlogRevision{
+		BlogID:    blog.ID,
+		Revision:  blog.Revision,
+		Content:   blog.Content,
+		EditedAt:  time.Now(),
+		ExpiresAt: blog.ExpiresAt,
+	}
+	if err := b.revisionRepository.Create(txCtx, revision); err != nil {
+		return entity.Blog{}, b.updateError(err)
+	}
+
+	previousTags := blog.Tags()
+	blog.Content = request.Content
+	blog.Revision++
+	res, err := b.blogRepository.Update(txCtx, blog)
+	if err != nil {
+		return entity.Blog{}, b.updateError(err)
+	}
+
+	if b.tagRepository != nil {
+		if err := b.tagRepository.SetTags(txCtx, *res, previousTags); err != nil {
+			return entity.Blog{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.Blog{}, err
+	}
+
+	if b.publisher != nil {
+		b.publisher.Publish(*res)
+	}
+
+	return *res, nil
+}
+
+// updateError maps an edit that lost to another edit or a delete to 409
+func (b BlogUseCase) updateError(err error) error {
+	if errors.Is(err, entity.ErrConflict) || errors.Is(err, entity.ErrNotFound) {
+		return fiber.ErrConflict
+	}
+	b.log.Warnf("Failed update blog : %+v", err)
+	return fiber.ErrInternalServerError
+}
+
+// DeleteBlog deletes one of the authenticated user's blogs and takes it off their blog count
+func (b BlogUseCase) DeleteBlog(ctx context.Context, blogID string) error {
+	blog, err := b.findOwnBlog(ctx, b.blogRepository, blogID)
+	if err != nil {
+		return err
+	}
+
+	tx, txCtx, err := b.uow.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := b.blogRepository.Delete(txCtx, blogID); err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		b.log.Warnf("Failed delete blog : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := b.blogCountRepository.Increment(txCtx, blog.AuthorID.String(), -1); err != nil {
+		return err
+	}
+
+	if b.tagRepository != nil {
+		if err := b.tagRepository.Remove(txCtx, blog); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if b.publisher != nil {
+		b.publisher.Retract(blog)
+	}
+
+	return nil
+}
+
+// findOwnBlog finds a blog and checks the authenticated user wrote it
+func (b BlogUseCase) findOwnBlog(ctx context.Context, blogRepository IBlog, blogID string) (entity.Blog, error) {
+	// Get authenticated user
+	user, err := authContext.GetUserFromContext(ctx)
+	if err != nil {
+		return entity.Blog{}, err
+	}
+
+	if _, err := uuid.Parse(blogID); err != nil {
+		return entity.Blog{}, fiber.ErrBadRequest
+	}
+
+	blog, err := blogRepository.FindById(ctx, blogID)
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			return entity.Blog{}, fiber.ErrNotFound
+		}
+		b.log.Warnf("Failed find blog : %+v", err)
+		return entity.Blog{}, fiber.ErrInternalServerError
+	}
+
+	if blog.AuthorID != user.ID {
+		return entity.Blog{}, fiber.ErrForbidden
+	}
+
+	return *blog, nil
 }
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+// IBlogCountRepo keeps a blog count per author, moved by deltas only
+type IBlogCountRepo interface {
+	Increment(ctx context.Context, authorID string, delta int64) error
+	Count(ctx context.Context, authorID string) (int64, error)
+}
+
+// IBlogCountSource counts the blog rows an author has in one store
+type IBlogCountSource interface {
+	CountByAuthor(ctx context.Context, authorID string) (int64, error)
+}
+
+type IUserPageRepo interface {
+	FindAll(ctx context.Context, page entity.Page) ([]*entity.User, string, error)
+}
+
+type BlogCountRepairConfig struct {
+	BatchSize   int
+	Concurrency int
+	DryRun      bool
+}
+
+// BlogCountRepairUseCase recomputes the blog counts of one store from its blog rows
+type BlogCountRepairUseCase struct {
+	log             *logrus.Logger
+	store           string
+	userRepository  IUserPageRepo
+	blogRepository  IBlogCountSource
+	countRepository IBlogCountRepo
+	config          BlogCountRepairConfig
+}
+
+func NewBlogCountRepairUseCase(logger *logrus.Logger, store string, userRepository IUserPageRepo, blogRepository IBlogCountSource,
+	countRepository IBlogCountRepo, config BlogCountRepairConfig) BlogCountRepairUseCase {
+	return BlogCountRepairUseCase{
+		log:             logger,
+		store:           store,
+		userRepository:  userRepository,
+		blogRepository:  blogRepository,
+		countRepository: countRepository,
+		config:          config,
+	}
+}
+
+// Repair walks every user and adds the difference between the counted rows and the stored count
+func (u BlogCountRepairUseCase) Repair(ctx context.Context) (entity.BlogCountRepairReport, error) {
+	report := entity.BlogCountRepairReport{Store: u.store, DryRun: u.config.DryRun}
+	var mu sync.Mutex
+
+	page := entity.Page{Limit: u.config.BatchSize}
+	for {
+		users, nextCursor, err := u.userRepository.FindAll(ctx, page)
+		if err != nil {
+			return report, err
+		}
+
+		group, groupCtx := errgroup.WithContext(ctx)
+		group.SetLimit(u.config.Concurrency)
+		for _, user := range users {
+			authorID := user.ID.String()
+			group.Go(func() error {
+				delta, err := u.repairAuthor(groupCtx, authorID)
+				if err != nil {
+					return err
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				report.Authors++
+				if delta != 0 {
+					report.Repaired++
+					report.Drift += max(delta, -delta)
+				}
+				return nil
+			})
+		}
+		if err := group.Wait(); err != nil {
+			return report, err
+		}
+		u.log.Infof("Checked the %s blog counts of %d authors, %d repaired", u.store, report.Authors, report.Repaired)
+
+		if nextCursor == "" {
+			return report, nil
+		}
+		page.Cursor = nextCursor
+	}
+}
+
+// repairAuthor returns the correction of one author's count, applied unless this is a dry run
+func (u BlogCountRepairUseCase) repairAuthor(ctx context.Context, authorID string) (int64, error) {
+	actual, err := u.blogRepository.CountByAuthor(ctx, authorID)
+	if err != nil {
+		return 0, err
+	}
+	stored, err := u.countRepository.Count(ctx, authorID)
+	if err != nil {
+		return 0, err
+	}
+
+	delta := actual - stored
+	if delta != 0 && !u.config.DryRun {
+		u.log.Debugf("Correcting %s blog count of %s from %d to %d", u.store, authorID, stored, actual)
+		if err := u.countRepository.Increment(ctx, authorID, delta); err != nil {
+			return 0, err
+		}
+	}
+
+	return delta, nil
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+)
+
+// IBlogRevisionRepo keeps the content blogs had before each edit
+type IBlogRevisionRepo interface {
+	Create(ctx context.Context, revision entity.BlogRevision) error
+	FindAll(ctx context.Context, blogID string) ([]*entity.BlogRevision, error)
+	FindByRevision(ctx context.Context, blogID string, revision int) (*entity.BlogRevision, error)
+}
+
+// maxDiffCells caps the line pairs a diff compares, bigger changes replace every line
+const maxDiffCells = 1 << 22
+
+// GetRevisions lists the previous revisions of one of the authenticated user's blogs, newest first
+func (b BlogUseCase) GetRevisions(ctx context.Context, blogID string) ([]entity.BlogRevision, error) {
+	if _, err := b.findOwnBlog(ctx, b.blogReadRepository, blogID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := b.revisionRepository.FindAll(ctx, blogID)
+	if err != nil {
+		b.log.Warnf("Failed find revisions : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	result := make([]entity.BlogRevision, len(revisions))
+	for i, revision := range revisions {
+		result[i] = *revision
+	}
+
+	return result, nil
+}
+
+// DiffRevisions diffs two revisions of one of the authenticated user's blogs line by line
+func (b BlogUseCase) DiffRevisions(ctx context.Context, blogID string, from int, to int) (entity.BlogDiff, error) {
+	blog, err := b.findOwnBlog(ctx, b.blogReadRepository, blogID)
+	if err != nil {
+		return entity.BlogDiff{}, err
+	}
+
+	fromContent, err := b.revisionContent(ctx, blog, from)
+	if err != nil {
+		return entity.BlogDiff{}, err
+	}
+	toContent, err := b.revisionContent(ctx, blog, to)
+	if err != nil {
+		return entity.BlogDiff{}, err
+	}
+
+	return entity.BlogDiff{
+		From:  from,
+		To:    to,
+		Lines: diffLines(strings.Split(fromContent, "\n"), strings.Split(toContent, "\n")),
+	}, nil
+}
+
+// revisionContent reads the content of a revision, the blog holds the current one
+func (b BlogUseCase) revisionContent(ctx context.Context, blog entity.Blog, revision int) (string, error) {
+	if revision < 1 || revision > blog.Revision {
+		return "", fiber.ErrBadRequest
+	}
+	if revision == blog.Revision {
+		return blog.Content, nil
+	}
+
+	found, err := b.revisionRepository.FindByRevision(ctx, blog.ID.String(), revision)
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			return "", fiber.ErrNotFound
+		}
+		b.log.Warnf("Failed find revision : %+v", err)
+		return "", fiber.ErrInternalServerError
+	}
+
+	return found.Content, nil
+}
+
+// diffLines diffs two texts line by line through their longest common subsequence
+func diffLines(a []string, b []string) []entity.DiffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]entity.DiffLine, 0, len(a)+len(b)-prefix-suffix)
+	for _, line := range a[:prefix] {
+		lines = append(lines, entity.DiffLine{Op: entity.DiffEqual, Text: line})
+	}
+	lines = append(lines, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, entity.DiffLine{Op: entity.DiffEqual, Text: line})
+	}
+
+	return lines
+}
+
+func diffMiddle(a []string, b []string) []entity.DiffLine {
+	var lines []entity.DiffLine
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			lines = append(lines, entity.DiffLine{Op: entity.DiffDelete, Text: line})
+		}
+		for _, line := range b {
+			lines = append(lines, entity.DiffLine{Op: entity.DiffInsert, Text: line})
+		}
+		return lines
+	}
+
+	// common[i][j] is the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, entity.DiffLine{Op: entity.DiffEqual, Text: a[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, entity.DiffLine{Op: entity.DiffDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, entity.DiffLine{Op: entity.DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, entity.DiffLine{Op: entity.DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, entity.DiffLine{Op: entity.DiffInsert, Text: b[j]})
+	}
+
+	return lines
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
+)
+
+type IBlogExpiryRepo interface {
+	DeleteExpired(ctx context.Context, now time.Time, limit int) ([]*entity.Blog, error)
+}
+
+type ExpirySweeperConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// ExpirySweeper deletes expired blogs and takes them off their authors' counts
+type ExpirySweeper struct {
+	uow             UnitOfWork
+	log             *logrus.Logger
+	blogRepository  IBlogExpiryRepo
+	countRepository IBlogCountRepo
+	config          ExpirySweeperConfig
+}
+
+func NewExpirySweeper(uow UnitOfWork, logger *logrus.Logger, blogRepository IBlogExpiryRepo, countRepository IBlogCountRepo,
+	config ExpirySweeperConfig) *ExpirySweeper {
+	return &ExpirySweeper{
+		uow:             uow,
+		log:             logger,
+		blogRepository:  blogRepository,
+		countRepository: countRepository,
+		config:          config,
+	}
+}
+
+// Run sweeps every interval until ctx is done, reads already hide expired blogs in between
+func (s *ExpirySweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Sweep(ctx); err != nil {
+				s.log.Warnf("Expiry sweep failed : %+v", err)
+			}
+		}
+	}
+}
+
+// Sweep deletes every blog expired by now in batches, so no single delete holds locks for long
+func (s *ExpirySweeper) Sweep(ctx context.Context) (int64, error) {
+	now := time.Now()
+
+	var total int64
+	for {
+		deleted, err := s.sweepBatch(ctx, now)
+		total += int64(deleted)
+		if err != nil {
+			return total, err
+		}
+		if deleted < s.config.BatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		s.log.Infof("Deleted %d expired blogs", total)
+	}
+	return total, nil
+}
+
+// sweepBatch deletes one batch and updates the counts in the same transaction
+func (s *ExpirySweeper) sweepBatch(ctx context.Context, now time.Time) (int, error) {
+	tx, txCtx, err := s.uow.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	blogs, err := s.blogRepository.DeleteExpired(txCtx, now, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	perAuthor := map[string]int64{}
+	for _, blog := range blogs {
+		perAuthor[blog.AuthorID.String()]++
+	}
+	for authorID, deleted := range perAuthor {
+		if err := s.countRepository.Increment(txCtx, authorID, -deleted); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(blogs), nil
+}
@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+type ITableScanner interface {
+	Columns(ctx context.Context, table string) ([]string, error)
+	ScanRange(ctx context.Context, table string, tokenRange entity.TokenRange, emit func(row map[string]interface{}) error) error
+}
+
+// IExportOutput is where rows go, an *os.File. Truncate and Sync are only used with a checkpoint.
+type IExportOutput interface {
+	io.Writer
+	Truncate(size int64) error
+	Sync() error
+}
+
+type ExportConfig struct {
+	Table            string
+	Format           string // ExportNDJSON or ExportCSV
+	Splits           int    // Token ranges the ring is cut into
+	Workers          int    // Ranges read at once
+	MaxRetries       int    // Further attempts of a failed range
+	RetryBackoff     time.Duration
+	ProgressInterval time.Duration
+}
+
+// ExportUseCase dumps a whole Cassandra table by reading token ranges in parallel
+type ExportUseCase struct {
+	log             *logrus.Logger
+	scanner         ITableScanner
+	output          IExportOutput
+	checkpointStore ICheckpointStore // nil when the output cannot be resumed, e.g. stdout
+	config          ExportConfig
+}
+
+func NewExportUseCase(logger *logrus.Logger, scanner ITableScanner, output IExportOutput, checkpointStore ICheckpointStore,
+	config ExportConfig) ExportUseCase {
+	return ExportUseCase{
+		log:             logger,
+		scanner:         scanner,
+		output:          output,
+		checkpointStore: checkpointStore,
+		config:          config,
+	}
+}
+
+// Run exports every range not in the checkpoint
+func (u ExportUseCase) Run(ctx context.Context) (entity.ExportReport, error) {
+	report := entity.ExportReport{Table: u.config.Table, Format: u.config.Format, Ranges: u.config.Splits}
+
+	encoder, err := newRowEncoder(u.config.Format)
+	if err != nil {
+		return report, err
+	}
+	columns, err := u.scanner.Columns(ctx, u.config.Table)
+	if err != nil {
+		return report, err
+	}
+
+	checkpoint, err := u.loadCheckpoint(ctx)
+	if err != nil {
+		return report, err
+	}
+	done := map[int]bool{}
+	for _, index := range checkpoint.Done {
+		done[index] = true
+	}
+	report.Skipped = len(done)
+
+	if checkpoint.OutputSize == 0 {
+		header, err := encoder.Header(columns)
+		if err != nil {
+			return report, err
+		}
+		if err := u.write(ctx, &checkpoint, header, -1); err != nil {
+			return report, err
+		}
+	}
+
+	var mu sync.Mutex // Guards the output, checkpoint and report
+	stop := u.reportProgress(&mu, &report)
+	defer stop()
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(u.config.Workers)
+	for index, tokenRange := range splitTokenRing(u.config.Splits) {
+		if done[index] {
+			continue
+		}
+		index, tokenRange := index, tokenRange
+		group.Go(func() error {
+			buf, rows, retries, err := u.exportRange(groupCtx, encoder, columns, tokenRange)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Retries += retries
+			if err != nil {
+				return err
+			}
+			if err := u.write(groupCtx, &checkpoint, buf.Bytes(), index); err != nil {
+				return err
+			}
+			report.Exported++
+			report.Rows += rows
+			return nil
+		})
+	}
+
+	return report, group.Wait()
+}
+
+// exportRange reads one range into a buffer, retrying it from the start on failure
+func (u ExportUseCase) exportRange(ctx context.Context, encoder rowEncoder, columns []string, tokenRange entity.TokenRange) (*bytes.Buffer, int64, int, error) {
+	backoff := u.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		var buf bytes.Buffer
+		var rows int64
+		err := u.scanner.ScanRange(ctx, u.config.Table, tokenRange, func(row map[string]interface{}) error {
+			rows++
+			return encoder.Encode(&buf, columns, row)
+		})
+		if err == nil || attempt >= u.config.MaxRetries || ctx.Err() != nil {
+			return &buf, rows, attempt, err
+		}
+
+		u.log.Warnf("Failed export token range (%d, %d], retrying in %s : %+v", tokenRange.Start, tokenRange.End, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return &buf, rows, attempt, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// write appends a range to the output and checkpoints it, index -1 is the header
+func (u ExportUseCase) write(ctx context.Context, checkpoint *entity.ExportCheckpoint, data []byte, index int) error {
+	if _, err := u.output.Write(data); err != nil {
+		return err
+	}
+	if u.checkpointStore == nil {
+		return nil
+	}
+
+	if err := u.output.Sync(); err != nil {
+		return err
+	}
+	checkpoint.OutputSize += int64(len(data))
+	if index >= 0 {
+		checkpoint.Done = append(checkpoint.Done, index)
+	}
+	return u.checkpointStore.Save(ctx, *checkpoint)
+}
+
+// loadCheckpoint resumes the checkpoint of the same export and cuts the output back to it
+func (u ExportUseCase) loadCheckpoint(ctx context.Context) (entity.ExportCheckpoint, error) {
+	fresh := entity.ExportCheckpoint{Table: u.config.Table, Format: u.config.Format, Splits: u.config.Splits}
+	if u.checkpointStore == nil {
+		return fresh, nil
+	}
+
+	var checkpoint entity.ExportCheckpoint
+	found, err := u.checkpointStore.Load(ctx, &checkpoint)
+	if err != nil {
+		return fresh, err
+	}
+	if !found {
+		checkpoint = fresh
+	}
+	if checkpoint.Table != fresh.Table || checkpoint.Format != fresh.Format || checkpoint.Splits != fresh.Splits {
+		return fresh, errors.New("checkpoint belongs to another table, format or split count, delete it to start over")
+	}
+
+	return checkpoint, u.output.Truncate(checkpoint.OutputSize)
+}
+
+// reportProgress logs the ranges and rows exported so far every ProgressInterval until stopped
+func (u ExportUseCase) reportProgress(mu *sync.Mutex, report *entity.ExportReport) func() {
+	if u.config.ProgressInterval <= 0 {
+		return func() {}
+	}
+
+	started := time.Now()
+	ticker := time.NewTicker(u.config.ProgressInterval)
+	stopped := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				mu.Lock()
+				done := report.Skipped + report.Exported
+				rows := report.Rows
+				mu.Unlock()
+				u.log.Infof("Exported %d of %d token ranges of %s, %d rows at %.0f rows/s",
+					done, report.Ranges, u.config.Table, rows, float64(rows)/time.Since(started).Seconds())
+			case <-stopped:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(stopped)
+	}
+}
+
+// splitTokenRing cuts the Murmur3 ring (-2^63, 2^63-1] into n contiguous ranges of near equal size
+func splitTokenRing(n int) []entity.TokenRange {
+	size := new(big.Int).Lsh(big.NewInt(1), 64) // Tokens in the ring, counting the unused minimum
+	ranges := make([]entity.TokenRange, n)
+	start := int64(math.MinInt64)
+	for i := range ranges {
+		end := int64(math.MaxInt64)
+		if i < n-1 {
+			// min + size*(i+1)/n, computed wide as it overflows int64
+			offset := new(big.Int).Div(new(big.Int).Mul(size, big.NewInt(int64(i+1))), big.NewInt(int64(n)))
+			end = new(big.Int).Add(big.NewInt(math.MinInt64), offset).Int64()
+		}
+		ranges[i] = entity.TokenRange{Start: start, End: end}
+		start = end
+	}
+	return ranges
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Export formats
+const (
+	ExportNDJSON = "ndjson"
+	ExportCSV    = "csv"
+)
+
+// rowEncoder turns scanned rows into lines of the export format
+type rowEncoder interface {
+	Header(columns []string) ([]byte, error)
+	Encode(buf *bytes.Buffer, columns []string, row map[string]interface{}) error
+}
+
+func newRowEncoder(format string) (rowEncoder, error) {
+	switch format {
+	case ExportNDJSON:
+		return ndjsonEncoder{}, nil
+	case ExportCSV:
+		return csvEncoder{}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// ndjsonEncoder writes one JSON object per row
+type ndjsonEncoder struct{}
+
+func (ndjsonEncoder) Header(columns []string) ([]byte, error) {
+	return nil, nil
+}
+
+func (ndjsonEncoder) Encode(buf *bytes.Buffer, columns []string, row map[string]interface{}) error {
+	return json.NewEncoder(buf).Encode(row)
+}
+
+// csvEncoder writes a header line and one record per row, collections as JSON
+type csvEncoder struct{}
+
+func (csvEncoder) Header(columns []string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func (csvEncoder) Encode(buf *bytes.Buffer, columns []string, row map[string]interface{}) error {
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		value, err := csvValue(row[column])
+		if err != nil {
+			return fmt.Errorf("column %s: %w", column, err)
+		}
+		record[i] = value
+	}
+
+	w := csv.NewWriter(buf)
+	if err := w.Write(record); err != nil {
+		return err
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func csvValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return "0x" + hex.EncodeToString(v), nil
+	case time.Time:
+		if v.IsZero() {
+			return "", nil
+		}
+		return v.UTC().Format(time.RFC3339Nano), nil
+	case fmt.Stringer:
+		// uuid, timeuuid, inet, duration
+		return v.String(), nil
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		raw, err := json.Marshal(value)
+		return string(raw), err
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
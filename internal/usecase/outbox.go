@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
+)
+
+type IOutboxRepo interface {
+	Create(ctx context.Context, event entity.OutboxEvent) (*entity.OutboxEvent, error)
+	FindPending(ctx context.Context, limit int) ([]*entity.OutboxEvent, error)
+	Lease(ctx context.Context, eventIDs []uuid.UUID, until time.Time) error
+	UpdateStatus(ctx context.Context, event entity.OutboxEvent) error
+	CountByStatus(ctx context.Context) (map[string]int64, error)
+}
+
+// OutboxHandler applies one outbox event to the secondary store, it may run more than once
+type OutboxHandler func(ctx context.Context, event entity.OutboxEvent) error
+
+type OutboxRelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+	Lease        time.Duration // How long claimed events are hidden from other relays
+}
+
+// OutboxRelay drains pending outbox events into the secondary store
+type OutboxRelay struct {
+	uow              UnitOfWork
+	log              *logrus.Logger
+	outboxRepository IOutboxRepo
+	handlers         map[string]OutboxHandler
+	config           OutboxRelayConfig
+}
+
+func NewOutboxRelay(uow UnitOfWork, logger *logrus.Logger, outboxRepository IOutboxRepo, config OutboxRelayConfig) *OutboxRelay {
+	return &OutboxRelay{
+		uow:              uow,
+		log:              logger,
+		outboxRepository: outboxRepository,
+		handlers:         map[string]OutboxHandler{},
+		config:           config,
+	}
+}
+
+// Handle registers the handler for an event type, it must be called before Run
+func (r *OutboxRelay) Handle(eventType string, handler OutboxHandler) {
+	r.handlers[eventType] = handler
+}
+
+// Run polls the outbox until ctx is done
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// keep draining while full batches come back
+			for {
+				processed, err := r.RelayOnce(ctx)
+				if err != nil {
+					r.log.Warnf("Outbox relay failed : %+v", err)
+					break
+				}
+				if processed < r.config.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// RelayOnce applies one batch of due events and returns how many were attempted
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	events, err := r.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// no transaction is open while the secondary store is written
+	for _, event := range events {
+		r.apply(ctx, event)
+	}
+
+	tx, txCtx, err := r.uow.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for _, event := range events {
+		if err := r.outboxRepository.UpdateStatus(txCtx, *event); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
+
+// claim leases a batch of due events to this relay in a short transaction
+func (r *OutboxRelay) claim(ctx context.Context) ([]*entity.OutboxEvent, error) {
+	tx, txCtx, err := r.uow.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	events, err := r.outboxRepository.FindPending(txCtx, r.config.BatchSize)
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	if err := r.outboxRepository.Lease(txCtx, ids, time.Now().Add(r.config.Lease)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Stats counts outbox events per status
+func (r *OutboxRelay) Stats(ctx context.Context) (map[string]int64, error) {
+	return r.outboxRepository.CountByStatus(ctx)
+}
+
+// apply runs the handler and moves the event to done, back to pending with a backoff, or to dead
+func (r *OutboxRelay) apply(ctx context.Context, event *entity.OutboxEvent) {
+	event.Attempts++
+
+	err := fmt.Errorf("no handler for event type %s", event.EventType)
+	if handler, ok := r.handlers[event.EventType]; ok {
+		err = handler(ctx, *event)
+	}
+
+	if err == nil {
+		event.Status = entity.OutboxStatusDone
+		event.LastError = ""
+		return
+	}
+
+	event.LastError = err.Error()
+	if event.Attempts >= r.config.MaxAttempts {
+		event.Status = entity.OutboxStatusDead
+		r.log.Errorf("Outbox event %s (%s) is dead after %d attempts : %+v", event.ID, event.EventType, event.Attempts, err)
+		return
+	}
+
+	event.NextAttemptAt = time.Now().Add(r.backoff(event.Attempts))
+	r.log.Warnf("Outbox event %s (%s) failed attempt %d, retrying at %s : %+v", event.ID, event.EventType, event.Attempts, event.NextAttemptAt, err)
+}
+
+// backoff doubles the delay for every failed attempt, up to BackoffMax
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	delay := r.config.BackoffBase
+	for i := 1; i < attempts && delay < r.config.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > r.config.BackoffMax {
+		delay = r.config.BackoffMax
+	}
+	return delay
+}
+
+// UserPayload is the outbox payload of user events, it keeps the password hash entity.User hides
+type UserPayload struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Username  string    `json:"username"`
+	Password  string    `json:"password"`
+	Token     string    `json:"token"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewUserCreatedEvent(user entity.User) (entity.OutboxEvent, error) {
+	return newUserEvent(entity.OutboxEventUserCreated, user)
+}
+
+// NewUserUpdatedEvent records the whole user after an update, not only the changed fields
+func NewUserUpdatedEvent(user entity.User) (entity.OutboxEvent, error) {
+	return newUserEvent(entity.OutboxEventUserUpdated, user)
+}
+
+func newUserEvent(eventType string, user entity.User) (entity.OutboxEvent, error) {
+	payload, err := json.Marshal(UserPayload{
+		ID:        user.ID,
+		Name:      user.Name,
+		Username:  user.Username,
+		Password:  user.Password,
+		Token:     user.Token,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	})
+	if err != nil {
+		return entity.OutboxEvent{}, err
+	}
+
+	return entity.OutboxEvent{
+		AggregateID: user.ID,
+		EventType:   eventType,
+		Payload:     payload,
+	}, nil
+}
+
+// NewUserCreatedHandler copies created users into the NoSQL user repository
+func NewUserCreatedHandler(userRepositoryNoSQL IUserRepoNoSQL) OutboxHandler {
+	return func(ctx context.Context, event entity.OutboxEvent) error {
+		user, err := userFromPayload(event.Payload)
+		if err != nil {
+			return err
+		}
+
+		_, err = userRepositoryNoSQL.Create(ctx, user)
+		return err
+	}
+}
+
+// NewUserUpdatedHandler applies updated users to the NoSQL user repository, skipping stale ones
+func NewUserUpdatedHandler(userRepositoryNoSQL IUserRepoNoSQL) OutboxHandler {
+	return func(ctx context.Context, event entity.OutboxEvent) error {
+		user, err := userFromPayload(event.Payload)
+		if err != nil {
+			return err
+		}
+
+		existing, err := userRepositoryNoSQL.FindById(ctx, user.ID.String())
+		if err != nil {
+			return err
+		}
+		if existing.UpdatedAt.After(user.UpdatedAt) {
+			return nil
+		}
+
+		_, err = userRepositoryNoSQL.Update(ctx, *existing, user)
+		return err
+	}
+}
+
+func userFromPayload(data []byte) (entity.User, error) {
+	var payload UserPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return entity.User{}, err
+	}
+
+	return entity.User{
+		ID:        payload.ID,
+		Name:      payload.Name,
+		Username:  payload.Username,
+		Password:  payload.Password,
+		Token:     payload.Token,
+		CreatedAt: payload.CreatedAt,
+		UpdatedAt: payload.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+type IBlogScanRepo interface {
+	FindPage(ctx context.Context, page entity.Page) ([]*entity.Blog, string, error)
+}
+
+type RebucketConfig struct {
+	BatchSize   int
+	Concurrency int
+	DryRun      bool
+}
+
+// RebucketUseCase rewrites the unbucketed blogs_by_author rows into the time bucketed layout
+type RebucketUseCase struct {
+	log                 *logrus.Logger
+	legacyRepository    IBlogScanRepo
+	blogRepositoryNoSQL IBlog
+	checkpointStore     ICheckpointStore
+	config              RebucketConfig
+}
+
+func NewRebucketUseCase(logger *logrus.Logger, legacyRepository IBlogScanRepo, blogRepositoryNoSQL IBlog,
+	checkpointStore ICheckpointStore, config RebucketConfig) RebucketUseCase {
+	return RebucketUseCase{
+		log:                 logger,
+		legacyRepository:    legacyRepository,
+		blogRepositoryNoSQL: blogRepositoryNoSQL,
+		checkpointStore:     checkpointStore,
+		config:              config,
+	}
+}
+
+// Run copies page by page, saving a checkpoint after every written page
+func (u RebucketUseCase) Run(ctx context.Context) (entity.RebucketReport, error) {
+	report := entity.RebucketReport{DryRun: u.config.DryRun}
+
+	if _, err := u.checkpointStore.Load(ctx, &report.Checkpoint); err != nil {
+		return report, err
+	}
+
+	for !report.Checkpoint.Done {
+		blogs, nextCursor, err := u.legacyRepository.FindPage(ctx, entity.Page{Limit: u.config.BatchSize, Cursor: report.Checkpoint.Cursor})
+		if err != nil {
+			return report, err
+		}
+
+		if !u.config.DryRun {
+			group, groupCtx := errgroup.WithContext(ctx)
+			group.SetLimit(u.config.Concurrency)
+			for _, blog := range blogs {
+				blog := blog
+				group.Go(func() error {
+					_, err := u.blogRepositoryNoSQL.Create(groupCtx, *blog)
+					return err
+				})
+			}
+			if err := group.Wait(); err != nil {
+				return report, err
+			}
+		}
+
+		report.Blogs += len(blogs)
+		report.Checkpoint.Cursor = nextCursor
+		report.Checkpoint.Done = nextCursor == ""
+		if !u.config.DryRun {
+			if err := u.checkpointStore.Save(ctx, report.Checkpoint); err != nil {
+				return report, err
+			}
+		}
+		u.log.Infof("Rebucketed %d blogs", report.Blogs)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,330 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
+)
+
+type IUserReconcileRepo interface {
+	IUserRepo
+	FindBatch(ctx context.Context, afterID string, limit int) ([]*entity.User, error)
+}
+
+type IUserReconcileRepoNoSQL interface {
+	IUserRepo
+	FindAll(ctx context.Context, page entity.Page) ([]*entity.User, string, error)
+}
+
+// IBlogReconcileRepoNoSQL is the Cassandra side of blog reconciling
+type IBlogReconcileRepoNoSQL interface {
+	IBlog
+	IBlogCopyRepo
+}
+
+type ReconcileConfig struct {
+	BatchSize int
+	Repair    string // One of the entity.Repair* directions
+	MaxDiffs  int    // Cap on the diffs listed in the report
+}
+
+// ReconcileUseCase finds and optionally repairs drift between the Postgres and Cassandra copies
+type ReconcileUseCase struct {
+	log                 *logrus.Logger
+	userRepository      IUserReconcileRepo
+	userRepositoryNoSQL IUserReconcileRepoNoSQL
+	blogRepository      IBlog
+	blogRepositoryNoSQL IBlogReconcileRepoNoSQL
+	config              ReconcileConfig
+}
+
+func NewReconcileUseCase(logger *logrus.Logger, userRepository IUserReconcileRepo, userRepositoryNoSQL IUserReconcileRepoNoSQL,
+	blogRepository IBlog, blogRepositoryNoSQL IBlogReconcileRepoNoSQL, config ReconcileConfig) ReconcileUseCase {
+	return ReconcileUseCase{
+		log:                 logger,
+		userRepository:      userRepository,
+		userRepositoryNoSQL: userRepositoryNoSQL,
+		blogRepository:      blogRepository,
+		blogRepositoryNoSQL: blogRepositoryNoSQL,
+		config:              config,
+	}
+}
+
+// Run walks the users and blogs of both stores, repairing them when repair is set
+func (r ReconcileUseCase) Run(ctx context.Context, repair bool) (entity.ReconcileReport, error) {
+	report := &entity.ReconcileReport{
+		StartedAt: time.Now(),
+		Repair:    entity.RepairNone,
+		Diffs:     []entity.ReconcileDiff{},
+	}
+	if repair && r.config.Repair != "" {
+		report.Repair = r.config.Repair
+	}
+
+	if err := r.reconcilePostgresUsers(ctx, report); err != nil {
+		return *report, err
+	}
+	if err := r.reconcileCassandraUsers(ctx, report); err != nil {
+		return *report, err
+	}
+
+	report.InSync = report.Users.MissingInCassandra+report.Users.MissingInPostgres+report.Users.Mismatched+
+		report.Blogs.MissingInCassandra+report.Blogs.MissingInPostgres+report.Blogs.Mismatched == 0
+	report.FinishedAt = time.Now()
+
+	return *report, nil
+}
+
+// reconcilePostgresUsers checks every Postgres user against Cassandra
+func (r ReconcileUseCase) reconcilePostgresUsers(ctx context.Context, report *entity.ReconcileReport) error {
+	afterID := ""
+	for {
+		users, err := r.userRepository.FindBatch(ctx, afterID, r.config.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+
+		for _, user := range users {
+			report.Users.Checked++
+
+			noSQLUser, err := r.userRepositoryNoSQL.FindById(ctx, user.ID.String())
+			switch {
+			case errors.Is(err, entity.ErrNotFound):
+				diff := entity.ReconcileDiff{Kind: "user", ID: user.ID.String(), Problem: entity.DriftMissingInCassandra}
+				if report.Repair == entity.RepairPostgresToCassandra {
+					_, err := r.userRepositoryNoSQL.Create(ctx, *user)
+					r.repaired(&diff, err)
+				}
+				r.addDiff(report, &report.Users, diff)
+			case err != nil:
+				return err
+			default:
+				if fields := userDiffFields(*user, *noSQLUser); len(fields) > 0 {
+					diff := entity.ReconcileDiff{Kind: "user", ID: user.ID.String(), Problem: entity.DriftMismatch, Fields: fields}
+					switch report.Repair {
+					case entity.RepairPostgresToCassandra:
+						_, err := r.userRepositoryNoSQL.Update(ctx, *noSQLUser, *user)
+						r.repaired(&diff, err)
+					case entity.RepairCassandraToPostgres:
+						_, err := r.userRepository.Update(ctx, *user, *noSQLUser)
+						r.repaired(&diff, err)
+					}
+					r.addDiff(report, &report.Users, diff)
+				}
+			}
+
+			if err := r.reconcileBlogs(ctx, report, user.ID.String()); err != nil {
+				return err
+			}
+		}
+
+		afterID = users[len(users)-1].ID.String()
+	}
+}
+
+// reconcileCassandraUsers finds the Cassandra users Postgres does not have
+func (r ReconcileUseCase) reconcileCassandraUsers(ctx context.Context, report *entity.ReconcileReport) error {
+	page := entity.Page{Limit: r.config.BatchSize}
+	for {
+		users, nextCursor, err := r.userRepositoryNoSQL.FindAll(ctx, page)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range users {
+			_, err := r.userRepository.FindById(ctx, user.ID.String())
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, entity.ErrNotFound) {
+				return err
+			}
+
+			report.Users.Checked++
+			diff := entity.ReconcileDiff{Kind: "user", ID: user.ID.String(), Problem: entity.DriftMissingInPostgres}
+			if report.Repair == entity.RepairCassandraToPostgres {
+				_, err := r.userRepository.Create(ctx, *user)
+				r.repaired(&diff, err)
+			}
+			r.addDiff(report, &report.Users, diff)
+
+			if err := r.reconcileBlogs(ctx, report, user.ID.String()); err != nil {
+				return err
+			}
+		}
+
+		if nextCursor == "" {
+			return nil
+		}
+		page.Cursor = nextCursor
+	}
+}
+
+// reconcileBlogs compares one author partition between both stores
+func (r ReconcileUseCase) reconcileBlogs(ctx context.Context, report *entity.ReconcileReport, authorID string) error {
+	blogs, err := r.findAllBlogs(ctx, r.blogRepository, authorID)
+	if err != nil {
+		return err
+	}
+	noSQLBlogs, err := r.findAllBlogs(ctx, r.blogRepositoryNoSQL, authorID)
+	if err != nil {
+		return err
+	}
+
+	for id, blog := range blogs {
+		report.Blogs.Checked++
+
+		noSQLBlog, ok := noSQLBlogs[id]
+		if !ok {
+			diff := entity.ReconcileDiff{Kind: "blog", ID: id, AuthorID: authorID, Problem: entity.DriftMissingInCassandra}
+			if report.Repair == entity.RepairPostgresToCassandra {
+				r.repaired(&diff, r.blogRepositoryNoSQL.Copy(ctx, *blog))
+			}
+			r.addDiff(report, &report.Blogs, diff)
+			continue
+		}
+
+		if fields := blogDiffFields(*blog, *noSQLBlog); len(fields) > 0 {
+			diff := entity.ReconcileDiff{Kind: "blog", ID: id, AuthorID: authorID, Problem: entity.DriftMismatch, Fields: fields}
+			switch {
+			case report.Repair == entity.RepairNone:
+			case blog.Ts.Unix() != noSQLBlog.Ts.Unix():
+				// ts is part of the Cassandra primary key, rewriting it would leave the old row behind
+				diff.Error = "ts differs, the row cannot be rewritten in place"
+			case report.Repair == entity.RepairPostgresToCassandra:
+				// Copy rewrites the row in place
+				r.repaired(&diff, r.blogRepositoryNoSQL.Copy(ctx, *blog))
+			case report.Repair == entity.RepairCassandraToPostgres && editOnly(fields):
+				// applies when Postgres is one revision behind, otherwise it fails as a conflict
+				_, err := r.blogRepository.Update(ctx, *noSQLBlog)
+				r.repaired(&diff, err)
+			case report.Repair == entity.RepairCassandraToPostgres:
+				diff.Error = "only the content of a blog can be updated"
+			}
+			r.addDiff(report, &report.Blogs, diff)
+		}
+	}
+
+	for id, noSQLBlog := range noSQLBlogs {
+		if _, ok := blogs[id]; ok {
+			continue
+		}
+
+		report.Blogs.Checked++
+		diff := entity.ReconcileDiff{Kind: "blog", ID: id, AuthorID: authorID, Problem: entity.DriftMissingInPostgres}
+		if report.Repair == entity.RepairCassandraToPostgres {
+			_, err := r.blogRepository.Create(ctx, *noSQLBlog)
+			r.repaired(&diff, err)
+		}
+		r.addDiff(report, &report.Blogs, diff)
+	}
+
+	return nil
+}
+
+// findAllBlogs reads every page of an author's blogs, keyed by blog ID
+func (r ReconcileUseCase) findAllBlogs(ctx context.Context, blogRepository IBlog, authorID string) (map[string]*entity.Blog, error) {
+	blogs := map[string]*entity.Blog{}
+
+	page := entity.Page{Limit: r.config.BatchSize}
+	for {
+		result, nextCursor, err := blogRepository.FindAll(ctx, authorID, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, blog := range result {
+			blogs[blog.ID.String()] = blog
+		}
+
+		if nextCursor == "" {
+			return blogs, nil
+		}
+		page.Cursor = nextCursor
+	}
+}
+
+func (r ReconcileUseCase) repaired(diff *entity.ReconcileDiff, err error) {
+	if err != nil {
+		r.log.Warnf("Failed to repair %s %s : %+v", diff.Kind, diff.ID, err)
+		diff.Error = err.Error()
+		return
+	}
+	diff.Repaired = true
+}
+
+func (r ReconcileUseCase) addDiff(report *entity.ReconcileReport, counts *entity.ReconcileCounts, diff entity.ReconcileDiff) {
+	switch diff.Problem {
+	case entity.DriftMissingInCassandra:
+		counts.MissingInCassandra++
+	case entity.DriftMissingInPostgres:
+		counts.MissingInPostgres++
+	case entity.DriftMismatch:
+		counts.Mismatched++
+	}
+	if diff.Repaired {
+		counts.Repaired++
+	}
+
+	if len(report.Diffs) >= r.config.MaxDiffs {
+		report.Truncated = true
+		return
+	}
+	report.Diffs = append(report.Diffs, diff)
+}
+
+func userDiffFields(a entity.User, b entity.User) []string {
+	var fields []string
+	if a.Name != b.Name {
+		fields = append(fields, "name")
+	}
+	if a.Username != b.Username {
+		fields = append(fields, "username")
+	}
+	if a.Password != b.Password {
+		fields = append(fields, "password")
+	}
+	if a.Token != b.Token {
+		fields = append(fields, "token")
+	}
+	return fields
+}
+
+// editOnly tells whether blogs differ only in what an edit changes
+func editOnly(fields []string) bool {
+	for _, field := range fields {
+		if field != "content" && field != "revision" {
+			return false
+		}
+	}
+	return true
+}
+
+// blogDiffFields compares blogs, ts only to the second since Postgres stores epoch seconds
+func blogDiffFields(a entity.Blog, b entity.Blog) []string {
+	var fields []string
+	if a.AuthorID != b.AuthorID {
+		fields = append(fields, "author_id")
+	}
+	if a.Username != b.Username {
+		fields = append(fields, "username")
+	}
+	if a.Content != b.Content {
+		fields = append(fields, "content")
+	}
+	if a.Ts.Unix() != b.Ts.Unix() {
+		fields = append(fields, "ts")
+	}
+	if a.ExpiresAt.Unix() != b.ExpiresAt.Unix() {
+		fields = append(fields, "expires_at")
+	}
+	if a.Revision != b.Revision {
+		fields = append(fields, "revision")
+	}
+	return fields
+}
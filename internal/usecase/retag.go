@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+type RetagConfig struct {
+	BatchSize   int
+	Concurrency int
+	DryRun      bool
+	Before      time.Time // Only blogs written before it are filed, zero files every blog
+}
+
+// RetagUseCase files the blogs written before tags existed under their #tags
+type RetagUseCase struct {
+	log             *logrus.Logger
+	blogRepository  IBlogScanRepo
+	tagRepository   IBlogTagRepo
+	checkpointStore ICheckpointStore
+	config          RetagConfig
+}
+
+func NewRetagUseCase(logger *logrus.Logger, blogRepository IBlogScanRepo, tagRepository IBlogTagRepo,
+	checkpointStore ICheckpointStore, config RetagConfig) RetagUseCase {
+	return RetagUseCase{
+		log:             logger,
+		blogRepository:  blogRepository,
+		tagRepository:   tagRepository,
+		checkpointStore: checkpointStore,
+		config:          config,
+	}
+}
+
+// Run files page by page, saving a checkpoint after every fully filed page
+func (u RetagUseCase) Run(ctx context.Context) (entity.RetagReport, error) {
+	report := entity.RetagReport{DryRun: u.config.DryRun}
+
+	if _, err := u.checkpointStore.Load(ctx, &report.Checkpoint); err != nil {
+		return report, err
+	}
+
+	for !report.Checkpoint.Done {
+		blogs, nextCursor, err := u.blogRepository.FindPage(ctx, entity.Page{Limit: u.config.BatchSize, Cursor: report.Checkpoint.Cursor})
+		if err != nil {
+			return report, err
+		}
+
+		var tagged []*entity.Blog
+		for _, blog := range blogs {
+			if len(blog.Tags()) == 0 || (!u.config.Before.IsZero() && !blog.Ts.Before(u.config.Before)) {
+				continue
+			}
+			tagged = append(tagged, blog)
+		}
+
+		if !u.config.DryRun {
+			group, groupCtx := errgroup.WithContext(ctx)
+			group.SetLimit(u.config.Concurrency)
+			for _, blog := range tagged {
+				blog := blog
+				group.Go(func() error {
+					return u.tagRepository.SetTags(groupCtx, *blog, nil)
+				})
+			}
+			if err := group.Wait(); err != nil {
+				return report, err
+			}
+		}
+
+		report.Blogs += len(blogs)
+		report.Tagged += len(tagged)
+		report.Checkpoint.Cursor = nextCursor
+		report.Checkpoint.Done = nextCursor == ""
+		if !u.config.DryRun {
+			if err := u.checkpointStore.Save(ctx, report.Checkpoint); err != nil {
+				return report, err
+			}
+		}
+		u.log.Infof("Retagged %d of %d blogs", report.Tagged, report.Blogs)
+	}
+
+	return report, nil
+}
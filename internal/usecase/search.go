@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	authContext "github.com/rifkiadrn/cassandra-explore/internal/handler/rest/context"
+	"github.com/sirupsen/logrus"
+)
+
+// IBlogSearchRepo finds the blogs of some authors containing every word of a query, best match first
+type IBlogSearchRepo interface {
+	Search(ctx context.Context, query string, authorIDs []uuid.UUID, page entity.Page) ([]*entity.Blog, string, error)
+}
+
+// ISearchIndex is a full-text index of blog content for stores that cannot search themselves
+type ISearchIndex interface {
+	Index(ctx context.Context, blog entity.Blog) error
+	Remove(ctx context.Context, blogID string) error
+	Search(ctx context.Context, query string, authorIDs []uuid.UUID, offset int, limit int) ([]uuid.UUID, error)
+}
+
+// maxSearchQuery caps the length of a search query in bytes
+const maxSearchQuery = 256
+
+// WithSearch serves SearchBlogs from searchRepository
+func (b BlogUseCase) WithSearch(searchRepository IBlogSearchRepo, followRepository IFollowRepo) BlogUseCase {
+	b.searchRepository = searchRepository
+	b.followRepository = followRepository
+	return b
+}
+
+// WithSearchIndexRebuilder fails searches with 503 until rebuilder has filled the index
+func (b BlogUseCase) WithSearchIndexRebuilder(rebuilder *SearchIndexRebuilder) BlogUseCase {
+	b.searchIndexRebuilder = rebuilder
+	return b
+}
+
+// SearchBlogs finds one page of the blogs visible to the authenticated user matching query
+func (b BlogUseCase) SearchBlogs(ctx context.Context, query string, page entity.Page) ([]entity.Blog, string, error) {
+	// Get authenticated user
+	user, err := authContext.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if b.searchRepository == nil {
+		return nil, "", fiber.ErrNotImplemented
+	}
+	if b.searchIndexRebuilder != nil && !b.searchIndexRebuilder.Rebuilt() {
+		return nil, "", fiber.ErrServiceUnavailable
+	}
+
+	query = strings.TrimSpace(query)
+	if query == "" || len(query) > maxSearchQuery {
+		return nil, "", fiber.ErrBadRequest
+	}
+	if page.Limit == 0 {
+		page.Limit = DefaultPageLimit
+	}
+	if page.Limit < 0 || page.Limit > MaxPageLimit {
+		return nil, "", fiber.ErrBadRequest
+	}
+
+	following, err := b.followRepository.FindFollowing(ctx, user.ID.String())
+	if err != nil {
+		b.log.Warnf("Failed find following : %+v", err)
+		return nil, "", fiber.ErrInternalServerError
+	}
+	authorIDs := append([]uuid.UUID{user.ID}, following...)
+
+	blogs, nextCursor, err := b.searchRepository.Search(ctx, query, authorIDs, page)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidCursor) {
+			b.log.Warnf("Invalid cursor : %+v", err)
+			return nil, "", fiber.ErrBadRequest
+		}
+		b.log.Warnf("Failed search blogs : %+v", err)
+		return nil, "", fiber.ErrInternalServerError
+	}
+
+	result := make([]entity.Blog, len(blogs))
+	for i, blog := range blogs {
+		result[i] = *blog
+	}
+
+	return result, nextCursor, nil
+}
+
+type SearchIndexRebuilderConfig struct {
+	BatchSize int
+}
+
+// SearchIndexRebuilder fills an embedded search index from the blog store
+type SearchIndexRebuilder struct {
+	log            *logrus.Logger
+	blogRepository IBlogScanRepo
+	index          ISearchIndex
+	config         SearchIndexRebuilderConfig
+	rebuilt        *atomic.Bool
+}
+
+func NewSearchIndexRebuilder(logger *logrus.Logger, blogRepository IBlogScanRepo, index ISearchIndex, config SearchIndexRebuilderConfig) SearchIndexRebuilder {
+	return SearchIndexRebuilder{
+		log:            logger,
+		blogRepository: blogRepository,
+		index:          index,
+		config:         config,
+		rebuilt:        &atomic.Bool{},
+	}
+}
+
+// Run rebuilds the index once, searches stay unavailable if it fails
+func (u SearchIndexRebuilder) Run(ctx context.Context) {
+	u.log.Warn("Rebuilding search index, searches are unavailable until it is done")
+	indexed, err := u.Rebuild(ctx)
+	if err != nil {
+		u.log.Errorf("Search index rebuild failed after %d blogs, searches stay unavailable : %+v", indexed, err)
+		return
+	}
+	u.rebuilt.Store(true)
+	u.log.Infof("Search index rebuilt with %d blogs", indexed)
+}
+
+// Rebuilt tells whether Run has filled the index
+func (u SearchIndexRebuilder) Rebuilt() bool {
+	return u.rebuilt.Load()
+}
+
+// Rebuild indexes every blog page by page and returns how many it indexed
+func (u SearchIndexRebuilder) Rebuild(ctx context.Context) (int, error) {
+	indexed := 0
+	page := entity.Page{Limit: u.config.BatchSize}
+	for {
+		blogs, nextCursor, err := u.blogRepository.FindPage(ctx, page)
+		if err != nil {
+			return indexed, err
+		}
+
+		for _, blog := range blogs {
+			if err := u.index.Index(ctx, *blog); err != nil {
+				return indexed, err
+			}
+		}
+		indexed += len(blogs)
+
+		if nextCursor == "" {
+			return indexed, nil
+		}
+		page.Cursor = nextCursor
+	}
+}
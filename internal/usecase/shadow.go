@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	"github.com/sirupsen/logrus"
+)
+
+type ShadowConfig struct {
+	SamplePercent float64 // Share of reads repeated on the secondary store, 0 to 100
+	Timeout       time.Duration
+}
+
+// ShadowReader repeats a sample of reads on the secondary store in the background and counts the mismatches
+type ShadowReader struct {
+	log    *logrus.Logger
+	config ShadowConfig
+	mu     sync.Mutex
+	stats  map[string]*entity.ShadowStats
+}
+
+func NewShadowReader(logger *logrus.Logger, config ShadowConfig) *ShadowReader {
+	return &ShadowReader{
+		log:    logger,
+		config: config,
+		stats:  map[string]*entity.ShadowStats{},
+	}
+}
+
+// Compare runs compare, which returns the differing fields, in the background for a sample of calls
+func (s *ShadowReader) Compare(resource string, key string, compare func(ctx context.Context) ([]string, error)) {
+	if rand.Float64()*100 >= s.config.SamplePercent {
+		return
+	}
+
+	go func() {
+		// the request context is recycled once the handler returns
+		ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+		defer cancel()
+
+		fields, err := compare(ctx)
+
+		s.mu.Lock()
+		stats, ok := s.stats[resource]
+		if !ok {
+			stats = &entity.ShadowStats{}
+			s.stats[resource] = stats
+		}
+		switch {
+		case err != nil:
+			stats.Failed++
+		case len(fields) > 0:
+			stats.Compared++
+			stats.Mismatched++
+		default:
+			stats.Compared++
+		}
+		s.mu.Unlock()
+
+		if err != nil {
+			s.log.Warnf("Shadow read of %s %s failed : %+v", resource, key, err)
+			return
+		}
+		if len(fields) > 0 {
+			s.log.Warnf("Shadow read of %s %s mismatched on %v", resource, key, fields)
+		}
+	}()
+}
+
+// Stats reports the counters per resource
+func (s *ShadowReader) Stats(ctx context.Context) (map[string]entity.ShadowStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make(map[string]entity.ShadowStats, len(s.stats))
+	for resource, counts := range s.stats {
+		stats[resource] = *counts
+	}
+
+	return stats, nil
+}
+
+// blogPageDiffFields compares two pages of blogs by ID, looking up a blog on one page only in the other store
+func blogPageDiffFields(ctx context.Context, primary []*entity.Blog, secondary []*entity.Blog, primaryRepository IBlog, secondaryRepository IBlog) ([]string, error) {
+	secondaryByID := make(map[string]*entity.Blog, len(secondary))
+	for _, blog := range secondary {
+		secondaryByID[blog.ID.String()] = blog
+	}
+
+	var fields []string
+	for _, blog := range primary {
+		id := blog.ID.String()
+		other, ok := secondaryByID[id]
+		if ok {
+			delete(secondaryByID, id)
+		} else {
+			found, err := secondaryRepository.FindById(ctx, id)
+			if errors.Is(err, entity.ErrNotFound) {
+				fields = append(fields, id+" missing")
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			other = found
+		}
+
+		for _, field := range blogDiffFields(*blog, *other) {
+			fields = append(fields, id+"."+field)
+		}
+	}
+	for id := range secondaryByID {
+		_, err := primaryRepository.FindById(ctx, id)
+		if errors.Is(err, entity.ErrNotFound) {
+			fields = append(fields, id+" extra")
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return fields, nil
+}
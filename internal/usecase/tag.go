@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	authContext "github.com/rifkiadrn/cassandra-explore/internal/handler/rest/context"
+	"github.com/sirupsen/logrus"
+)
+
+// IBlogTagRepo lists blogs by the #tags of their content and counts how often tags are used
+type IBlogTagRepo interface {
+	// SetTags files the blog under blog.Tags(), previous are the tags it was filed under before an edit
+	SetTags(ctx context.Context, blog entity.Blog, previous []string) error
+	// Remove takes a deleted blog off its tags
+	Remove(ctx context.Context, blog entity.Blog) error
+	// FindByTag lists the blogs of some authors under a tag, newest first
+	FindByTag(ctx context.Context, tag string, authorIDs []uuid.UUID, page entity.Page) ([]*entity.Blog, string, error)
+	// TopTags counts the blogs written since the time under each tag, most used first
+	TopTags(ctx context.Context, since time.Time, limit int) ([]entity.TagCount, error)
+}
+
+// WithTags files created and edited blogs under their #tags
+func (b BlogUseCase) WithTags(tagRepository IBlogTagRepo) BlogUseCase {
+	b.tagRepository = tagRepository
+	return b
+}
+
+type TagConfig struct {
+	DefaultWindow time.Duration
+	MaxWindow     time.Duration
+}
+
+const DefaultTopTags = 10
+
+// TagUseCase serves the blogs of a tag and the most used tags
+type TagUseCase struct {
+	log              *logrus.Logger
+	tagRepository    IBlogTagRepo
+	followRepository IFollowRepo
+	config           TagConfig
+}
+
+func NewTagUseCase(logger *logrus.Logger, tagRepository IBlogTagRepo, followRepository IFollowRepo, config TagConfig) TagUseCase {
+	return TagUseCase{
+		log:              logger,
+		tagRepository:    tagRepository,
+		followRepository: followRepository,
+		config:           config,
+	}
+}
+
+// GetTagBlogs finds one page of the visible blogs filed under a tag, newest first
+func (t TagUseCase) GetTagBlogs(ctx context.Context, tag string, page entity.Page) ([]entity.Blog, string, error) {
+	// Get authenticated user
+	user, err := authContext.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tag, ok := entity.NormalizeTag(tag)
+	if !ok {
+		return nil, "", fiber.ErrBadRequest
+	}
+
+	if page.Limit == 0 {
+		page.Limit = DefaultPageLimit
+	}
+	if page.Limit < 0 || page.Limit > MaxPageLimit {
+		return nil, "", fiber.ErrBadRequest
+	}
+
+	following, err := t.followRepository.FindFollowing(ctx, user.ID.String())
+	if err != nil {
+		t.log.Warnf("Failed find following : %+v", err)
+		return nil, "", fiber.ErrInternalServerError
+	}
+	authorIDs := append([]uuid.UUID{user.ID}, following...)
+
+	blogs, nextCursor, err := t.tagRepository.FindByTag(ctx, tag, authorIDs, page)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidCursor) {
+			t.log.Warnf("Invalid cursor : %+v", err)
+			return nil, "", fiber.ErrBadRequest
+		}
+		t.log.Warnf("Failed find tag blogs : %+v", err)
+		return nil, "", fiber.ErrInternalServerError
+	}
+
+	result := make([]entity.Blog, len(blogs))
+	for i, blog := range blogs {
+		result[i] = *blog
+	}
+
+	return result, nextCursor, nil
+}
+
+// GetTopTags counts the tags of the blogs written in the last window, most used first
+func (t TagUseCase) GetTopTags(ctx context.Context, window time.Duration, limit int) ([]entity.TagCount, error) {
+	if window == 0 {
+		window = t.config.DefaultWindow
+	}
+	if window < 0 || window > t.config.MaxWindow {
+		return nil, fiber.ErrBadRequest
+	}
+	if limit == 0 {
+		limit = DefaultTopTags
+	}
+	if limit < 0 || limit > MaxPageLimit {
+		return nil, fiber.ErrBadRequest
+	}
+
+	tags, err := t.tagRepository.TopTags(ctx, time.Now().Add(-window), limit)
+	if err != nil {
+		t.log.Warnf("Failed count tags : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return tags, nil
+}
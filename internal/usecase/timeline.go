@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rifkiadrn/cassandra-explore/internal/entity"
+	authContext "github.com/rifkiadrn/cassandra-explore/internal/handler/rest/context"
+	model_api "github.com/rifkiadrn/cassandra-explore/internal/model/api"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+type IFollowRepo interface {
+	Follow(ctx context.Context, followerID string, followedID string) error
+	Unfollow(ctx context.Context, followerID string, followedID string) error
+	FindFollowers(ctx context.Context, userID string, page entity.Page) ([]uuid.UUID, string, error)
+	FindFollowing(ctx context.Context, userID string) ([]uuid.UUID, error)
+	CountFollowers(ctx context.Context, userID string) (int64, error)
+}
+
+type ITimelineRepo interface {
+	Add(ctx context.Context, readerID string, blog entity.Blog) error
+	Remove(ctx context.Context, readerID string, blog entity.Blog) error
+	FindBefore(ctx context.Context, readerID string, before entity.FeedPosition, limit int) ([]*entity.Blog, error)
+	MarkLargeAccount(ctx context.Context, userID string) error
+	FindLargeAccounts(ctx context.Context) ([]uuid.UUID, error)
+}
+
+type TimelineConfig struct {
+	FanoutThreshold   int64 // Authors with more followers are merged in on read instead of fanned out on write
+	FanoutBatchSize   int
+	FanoutConcurrency int
+	FanoutWorkers     int // Blogs fanned out at once, each with up to FanoutConcurrency writes
+	FanoutQueueSize   int // Blogs waiting for a worker, more are dropped
+}
+
+type fanoutJob struct {
+	blog   entity.Blog
+	write  func(ctx context.Context, readerID string, blog entity.Blog) error
+	action string
+}
+
+// TimelineUseCase builds home timelines, fanning blogs out on write except for very large accounts
+type TimelineUseCase struct {
+	log                *logrus.Logger
+	userRepository     IUserRepo
+	blogRepository     IBlog
+	followRepository   IFollowRepo
+	timelineRepository ITimelineRepo
+	config             TimelineConfig
+	queue              chan fanoutJob
+}
+
+func NewTimelineUseCase(logger *logrus.Logger, userRepository IUserRepo, blogRepository IBlog,
+	followRepository IFollowRepo, timelineRepository ITimelineRepo, config TimelineConfig) TimelineUseCase {
+	return TimelineUseCase{
+		log:                logger,
+		userRepository:     userRepository,
+		blogRepository:     blogRepository,
+		followRepository:   followRepository,
+		timelineRepository: timelineRepository,
+		config:             config,
+		queue:              make(chan fanoutJob, config.FanoutQueueSize),
+	}
+}
+
+// Follow makes the authenticated user follow userID
+func (t TimelineUseCase) Follow(ctx context.Context, userID string) error {
+	user, err := t.followTarget(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := t.followRepository.Follow(ctx, user.ID.String(), userID); err != nil {
+		t.log.Warnf("Failed follow user : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// Unfollow stops the authenticated user following userID
+func (t TimelineUseCase) Unfollow(ctx context.Context, userID string) error {
+	user, err := t.followTarget(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := t.followRepository.Unfollow(ctx, user.ID.String(), userID); err != nil {
+		t.log.Warnf("Failed unfollow user : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// followTarget returns the authenticated user after checking userID is someone else who exists
+func (t TimelineUseCase) followTarget(ctx context.Context, userID string) (model_api.Auth, error) {
+	user, err := authContext.GetUserFromContext(ctx)
+	if err != nil {
+		return model_api.Auth{}, err
+	}
+
+	if _, err := uuid.Parse(userID); err != nil || userID == user.ID.String() {
+		return model_api.Auth{}, fiber.ErrBadRequest
+	}
+
+	if _, err := t.userRepository.FindById(ctx, userID); err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			return model_api.Auth{}, fiber.ErrNotFound
+		}
+		t.log.Warnf("Failed find user : %+v", err)
+		return model_api.Auth{}, fiber.ErrInternalServerError
+	}
+
+	return user, nil
+}
+
+// Publish queues a new or updated blog for fan-out to its author's followers
+func (t TimelineUseCase) Publish(blog entity.Blog) {
+	t.enqueue(fanoutJob{blog: blog, write: t.timelineRepository.Add, action: "fan out"})
+}
+
+// Retract queues a deleted blog for removal from the timelines it was fanned out to
+func (t TimelineUseCase) Retract(blog entity.Blog) {
+	t.enqueue(fanoutJob{blog: blog, write: t.timelineRepository.Remove, action: "retract"})
+}
+
+// enqueue hands a job to the fan-out workers without blocking the request
+func (t TimelineUseCase) enqueue(job fanoutJob) {
+	select {
+	case t.queue <- job:
+	default:
+		t.log.Warnf("Fan-out queue full, failed %s blog %s", job.action, job.blog.ID)
+	}
+}
+
+// Run fans out queued blogs until ctx is done, then drains the queue
+func (t TimelineUseCase) Run(ctx context.Context) {
+	var workers sync.WaitGroup
+	for i := 0; i < t.config.FanoutWorkers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			t.work(ctx)
+		}()
+	}
+	workers.Wait()
+}
+
+func (t TimelineUseCase) work(ctx context.Context) {
+	for {
+		select {
+		case job := <-t.queue:
+			// the request context is recycled once the handler returns
+			t.apply(context.Background(), job)
+		case <-ctx.Done():
+			for {
+				select {
+				case job := <-t.queue:
+					t.apply(context.Background(), job)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (t TimelineUseCase) apply(ctx context.Context, job fanoutJob) {
+	if err := t.fanout(ctx, job.blog, job.write); err != nil {
+		t.log.Warnf("Failed %s blog %s : %+v", job.action, job.blog.ID, err)
+	}
+}
+
+// fanout applies write to the timeline of every follower, or marks an author above the threshold
+func (t TimelineUseCase) fanout(ctx context.Context, blog entity.Blog, write func(ctx context.Context, readerID string, blog entity.Blog) error) error {
+	followers, err := t.followRepository.CountFollowers(ctx, blog.AuthorID.String())
+	if err != nil {
+		return err
+	}
+	if followers > t.config.FanoutThreshold {
+		t.log.Debugf("Author %s has %d followers, blog %s is read on demand", blog.AuthorID, followers, blog.ID)
+		// the mark stays when followers drop again, blogs published meanwhile are only found on read
+		return t.timelineRepository.MarkLargeAccount(ctx, blog.AuthorID.String())
+	}
+
+	page := entity.Page{Limit: t.config.FanoutBatchSize}
+	for {
+		readers, nextCursor, err := t.followRepository.FindFollowers(ctx, blog.AuthorID.String(), page)
+		if err != nil {
+			return err
+		}
+
+		group, groupCtx := errgroup.WithContext(ctx)
+		group.SetLimit(t.config.FanoutConcurrency)
+		for _, reader := range readers {
+			reader := reader
+			group.Go(func() error {
+				return write(groupCtx, reader.String(), blog)
+			})
+		}
+		if err := group.Wait(); err != nil {
+			return err
+		}
+
+		if nextCursor == "" {
+			return nil
+		}
+		page.Cursor = nextCursor
+	}
+}
+
+// GetTimeline reads one page of the authenticated user's home timeline, newest first
+func (t TimelineUseCase) GetTimeline(ctx context.Context, page entity.Page) ([]entity.Blog, string, error) {
+	// Get authenticated user
+	user, err := authContext.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if page.Limit == 0 {
+		page.Limit = DefaultPageLimit
+	}
+	if page.Limit < 0 || page.Limit > MaxPageLimit {
+		return nil, "", fiber.ErrBadRequest
+	}
+
+	before, err := entity.DecodeFeedPosition(page.Cursor)
+	if err != nil {
+		t.log.Warnf("Invalid cursor : %+v", err)
+		return nil, "", fiber.ErrBadRequest
+	}
+
+	following, err := t.followRepository.FindFollowing(ctx, user.ID.String())
+	if err != nil {
+		return nil, "", err
+	}
+	followed := map[uuid.UUID]bool{user.ID: true}
+	for _, id := range following {
+		followed[id] = true
+	}
+
+	pulled, err := t.largeAccounts(ctx, following)
+	if err != nil {
+		return nil, "", err
+	}
+	pulled = append(pulled, user.ID)
+
+	// every source returns up to page.Limit blogs before the position
+	sources := make([][]*entity.Blog, len(pulled)+1)
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(t.config.FanoutConcurrency)
+	group.Go(func() error {
+		blogs, err := t.timelineRepository.FindBefore(groupCtx, user.ID.String(), before, page.Limit)
+		sources[0] = blogs
+		return err
+	})
+	for i, authorID := range pulled {
+		i, authorID := i, authorID
+		group.Go(func() error {
+			blogs, err := t.blogRepository.FindBefore(groupCtx, authorID.String(), before, page.Limit)
+			sources[i+1] = blogs
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, "", err
+	}
+
+	result := mergeFeed(sources, followed, page.Limit)
+
+	nextCursor := ""
+	if len(result) == page.Limit {
+		last := result[len(result)-1]
+		nextCursor = entity.FeedPositionOf(last).Encode()
+	}
+
+	return result, nextCursor, nil
+}
+
+// largeAccounts returns the followed accounts whose blogs were not fanned out
+func (t TimelineUseCase) largeAccounts(ctx context.Context, following []uuid.UUID) ([]uuid.UUID, error) {
+	large, err := t.timelineRepository.FindLargeAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	marked := make(map[uuid.UUID]bool, len(large))
+	for _, id := range large {
+		marked[id] = true
+	}
+
+	var accounts []uuid.UUID
+	for _, id := range following {
+		if marked[id] {
+			accounts = append(accounts, id)
+		}
+	}
+	return accounts, nil
+}
+
+// mergeFeed merges the sources in feed order, dropping duplicates and unfollowed authors
+func mergeFeed(sources [][]*entity.Blog, followed map[uuid.UUID]bool, limit int) []entity.Blog {
+	seen := map[uuid.UUID]bool{}
+	var merged []entity.Blog
+	for _, blogs := range sources {
+		for _, blog := range blogs {
+			if seen[blog.ID] || !followed[blog.AuthorID] {
+				continue
+			}
+			seen[blog.ID] = true
+			merged = append(merged, *blog)
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return entity.FeedPositionOf(merged[i]).Before(entity.FeedPositionOf(merged[j]))
+	})
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+
+	return merged
+}
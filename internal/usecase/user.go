@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -22,31 +23,49 @@ type IUserRepo interface {
 	Update(ctx context.Context, existingUser entity.User, updatedUser entity.User) (*entity.User, error)
 }
 
+// IUserRepoNoSQL is the Cassandra copy of users the outbox relay keeps in step
 type IUserRepoNoSQL interface {
 	Create(ctx context.Context, user entity.User) (*entity.User, error)
+	FindById(ctx context.Context, userID string) (*entity.User, error)
+	Update(ctx context.Context, existingUser entity.User, updatedUser entity.User) (*entity.User, error)
 }
 
 type UserUseCase struct {
-	uow                 UnitOfWork
-	log                 *logrus.Logger
-	validate            *validator.Validate
-	userRepository      IUserRepo
-	userRepositoryNoSQL IUserRepoNoSQL
-	jwtManager          *utils.JWTManager
+	uow                  UnitOfWork
+	log                  *logrus.Logger
+	validate             *validator.Validate
+	userRepository       IUserRepo
+	outboxRepository     IOutboxRepo
+	blogCountRepository  IBlogCountRepo
+	jwtManager           *utils.JWTManager
+	userReadRepository   IUserRepo // Serves FindById, the primary store of shadow reads
+	userShadowRepository IUserRepo
+	shadowReader         *ShadowReader
 }
 
+// NewUserUseCase creates the user use case, outboxRepository is nil when users are not replicated
 func NewUserUseCase(uow UnitOfWork, logger *logrus.Logger, validate *validator.Validate,
-	userRepository IUserRepo, userRepositoryNoSQL IUserRepoNoSQL, jwtManager *utils.JWTManager) UserUseCase {
+	userRepository IUserRepo, outboxRepository IOutboxRepo, blogCountRepository IBlogCountRepo, jwtManager *utils.JWTManager) UserUseCase {
 	return UserUseCase{
 		uow:                 uow,
 		log:                 logger,
 		validate:            validate,
 		userRepository:      userRepository,
-		userRepositoryNoSQL: userRepositoryNoSQL,
+		outboxRepository:    outboxRepository,
+		blogCountRepository: blogCountRepository,
 		jwtManager:          jwtManager,
+		userReadRepository:  userRepository,
 	}
 }
 
+// WithShadowReads serves FindById from primary and repeats a sample of the reads on secondary
+func (userUC UserUseCase) WithShadowReads(primary IUserRepo, secondary IUserRepo, shadowReader *ShadowReader) UserUseCase {
+	userUC.userReadRepository = primary
+	userUC.userShadowRepository = secondary
+	userUC.shadowReader = shadowReader
+	return userUC
+}
+
 func (userUC UserUseCase) Verify(ctx context.Context, request model_api.VerifyUserRequest) (model_api.Auth, error) {
 	// Validate request
 	if err := userUC.validate.Struct(request); err != nil {
@@ -115,22 +134,33 @@ func (userUC UserUseCase) Register(ctx context.Context, request model.RegisterUs
 
 	// Create user via repository
 	createdUser, err := userUC.userRepository.Create(txCtx, userEntity)
+	if errors.Is(err, entity.ErrConflict) {
+		return model.User{}, fiber.ErrConflict
+	}
 	if err != nil {
 		userUC.log.Warnf("Failed create user : %+v", err)
 		return model.User{}, fiber.ErrInternalServerError
 	}
 
+	// Record the cassandra copy in the same transaction, the outbox relay applies it
+	if userUC.outboxRepository != nil {
+		event, err := NewUserCreatedEvent(*createdUser)
+		if err != nil {
+			userUC.log.Warnf("Failed build outbox event : %+v", err)
+			return model.User{}, fiber.ErrInternalServerError
+		}
+		if _, err := userUC.outboxRepository.Create(txCtx, event); err != nil {
+			userUC.log.Warnf("Failed create outbox event : %+v", err)
+			return model.User{}, fiber.ErrInternalServerError
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		userUC.log.Warnf("Failed commit transaction : %+v", err)
 		return model.User{}, fiber.ErrInternalServerError
 	}
 
-	// double create to cassandra
-	_, _ = userUC.userRepositoryNoSQL.Create(ctx, *createdUser)
-
-	// Create user in Cassandra
-
 	// Convert domain entity to response DTO
 	return model.User{
 		Id:       (*createdUser).ID.String(),
@@ -180,13 +210,62 @@ func (userUC UserUseCase) Login(ctx context.Context, request model.LoginUser) (m
 }
 
 func (userUC UserUseCase) FindById(ctx context.Context, userID string) (entity.User, error) {
-	user, err := userUC.userRepository.FindById(ctx, userID)
+	user, err := userUC.userReadRepository.FindById(ctx, userID)
 	if err != nil {
 		return entity.User{}, err
 	}
+
+	if userUC.shadowReader != nil {
+		primary := *user
+		userUC.shadowReader.Compare("user", userID, func(ctx context.Context) ([]string, error) {
+			shadow, err := userUC.userShadowRepository.FindById(ctx, userID)
+			if errors.Is(err, entity.ErrNotFound) {
+				return []string{"missing"}, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return userDiffFields(primary, *shadow), nil
+		})
+	}
+
 	return *user, nil
 }
 
+// GetUser returns the profile of a user with the count of their blogs
+func (userUC UserUseCase) GetUser(ctx context.Context, userID string) (model.UserProfile, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return model.UserProfile{}, fiber.ErrBadRequest
+	}
+
+	user, err := userUC.FindById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			return model.UserProfile{}, fiber.ErrNotFound
+		}
+		userUC.log.Warnf("Failed find user : %+v", err)
+		return model.UserProfile{}, fiber.ErrInternalServerError
+	}
+
+	blogCount, err := userUC.blogCountRepository.Count(ctx, userID)
+	if err != nil {
+		userUC.log.Warnf("Failed count blogs : %+v", err)
+		return model.UserProfile{}, fiber.ErrInternalServerError
+	}
+
+	profile := model.UserProfile{
+		Id:        user.ID.String(),
+		Name:      user.Name,
+		Username:  user.Username,
+		BlogCount: blogCount,
+	}
+	if !user.CreatedAt.IsZero() {
+		createdAt := user.CreatedAt.Unix()
+		profile.CreatedAt = &createdAt
+	}
+	return profile, nil
+}
+
 func (userUC UserUseCase) UpdateUser(ctx context.Context, userID string, user entity.User) (entity.User, error) {
 	// Start transaction
 	tx, txCtx, err := userUC.uow.Begin(ctx)
@@ -200,10 +279,26 @@ func (userUC UserUseCase) UpdateUser(ctx context.Context, userID string, user en
 		return entity.User{}, err
 	}
 	updatedUser, err := userUC.userRepository.Update(txCtx, *existingUser, user)
+	if errors.Is(err, entity.ErrConflict) {
+		return entity.User{}, fiber.ErrConflict
+	}
 	if err != nil {
 		return entity.User{}, err
 	}
 
+	// Record the cassandra copy in the same transaction, like on register
+	if userUC.outboxRepository != nil {
+		event, err := NewUserUpdatedEvent(*updatedUser)
+		if err != nil {
+			userUC.log.Warnf("Failed build outbox event : %+v", err)
+			return entity.User{}, fiber.ErrInternalServerError
+		}
+		if _, err := userUC.outboxRepository.Create(txCtx, event); err != nil {
+			userUC.log.Warnf("Failed create outbox event : %+v", err)
+			return entity.User{}, fiber.ErrInternalServerError
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return entity.User{}, err
 	}